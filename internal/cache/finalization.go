@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+Finalizing a tournament pays out rewards rank by rank. The number of ranks already paid
+is kept as a checkpoint in Redis next to the leaderboard, so a finalization interrupted
+by a shutdown resumes where it stopped. The checkpoint only saves work: rewards are
+recorded in Postgres with the payment, which is what keeps anyone from being paid twice.
+A lock makes sure only one instance pays out a tournament at a time.
+*/
+
+func finalizationLockKey(tournamentID uuid.UUID) string {
+	return fmt.Sprintf("finalization_lock:%s", tournamentID)
+}
+
+func payoutCheckpointKey(tournamentID uuid.UUID) string {
+	return fmt.Sprintf("payout_checkpoint:%s", tournamentID)
+}
+
+// releaseLockScript deletes a lock only if it still holds the caller's token, so a holder whose
+// lock expired cannot release the lock another instance took since.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireFinalizationLock takes the payout lock of a tournament and returns the token that
+// releases it. It returns false if another finalization holds the lock. The lock expires
+// after ttl in case its holder dies.
+func AcquireFinalizationLock(ctx context.Context, tournamentID uuid.UUID, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+	locked, err := redisClient.SetNX(ctx, finalizationLockKey(tournamentID), token, ttl).Result()
+	if err != nil || !locked {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// ReleaseFinalizationLock releases the payout lock of a tournament if token still holds it.
+func ReleaseFinalizationLock(ctx context.Context, tournamentID uuid.UUID, token string) error {
+	return releaseLockScript.Run(ctx, redisClient, []string{finalizationLockKey(tournamentID)}, token).Err()
+}
+
+// GetPayoutCheckpoint returns how many ranks of a tournament were already paid out.
+func GetPayoutCheckpoint(ctx context.Context, tournamentID uuid.UUID) (int, error) {
+	paid, err := redisClient.Get(ctx, payoutCheckpointKey(tournamentID)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return paid, err
+}
+
+// SetPayoutCheckpoint records that the first paid ranks of a tournament were paid out.
+func SetPayoutCheckpoint(ctx context.Context, tournamentID uuid.UUID, paid int) error {
+	return redisClient.Set(ctx, payoutCheckpointKey(tournamentID), paid, 0).Err()
+}
+
+// DeletePayoutCheckpoint removes the checkpoint once a tournament is fully paid out.
+func DeletePayoutCheckpoint(ctx context.Context, tournamentID uuid.UUID) error {
+	return redisClient.Del(ctx, payoutCheckpointKey(tournamentID)).Err()
+}
+
+// GetPendingTournamentIDs returns every tournament that still has a solo or team leaderboard
+// in Redis. Leaderboards are deleted after the payout, so finished tournaments in this list
+// were interrupted during finalization.
+func GetPendingTournamentIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, prefix := range []string{"leaderboard:", "team_leaderboard:"} {
+		iter := redisClient.Scan(ctx, 0, prefix+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			id, err := uuid.Parse(strings.TrimPrefix(iter.Val(), prefix))
+			if err != nil {
+				// Global, country and rebuild keys share the prefix
+				continue
+			}
+			ids = append(ids, id)
+		}
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
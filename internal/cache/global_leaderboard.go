@@ -0,0 +1,325 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+Global and country leaderboards are kept in Redis sorted sets scored by user level.
+Only users who have competed in a tournament are ranked. Users are added when they
+enter a tournament and their score is refreshed whenever their level changes.
+Postgres stays the source of truth, the sets can be rebuilt from it at any time.
+
+A rebuild swaps in sets built from a Postgres read, so writes made in the meantime would
+be lost with the old sets. Every write therefore also records the user in a change journal,
+and the rebuild applies the users changed since it started again afterwards.
+*/
+
+const (
+	globalLeaderboardKey     = "leaderboard:global"
+	countryLeaderboardPrefix = "leaderboard:country:"
+	leaderboardChangesKey    = "leaderboard:changes"
+	rebuildBatchSize         = 1000
+	// Changes are journaled this long, longer than any rebuild takes
+	changeJournalRetention = time.Hour
+)
+
+func countryLeaderboardKey(country string) string {
+	return countryLeaderboardPrefix + country
+}
+
+// RankedUser is the data needed to place a user on the global and country leaderboards.
+type RankedUser struct {
+	UserID  uuid.UUID
+	Country string
+	Level   int
+}
+
+// AddUserToGlobalLeaderboards adds or updates a user on the global and country leaderboards.
+// Levels only go up, so a level read before a concurrent level-up never replaces the higher one.
+func AddUserToGlobalLeaderboards(ctx context.Context, userID uuid.UUID, country string, level int) error {
+	member := redis.Z{Score: float64(level), Member: userID.String()}
+	_, err := redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAddGT(ctx, globalLeaderboardKey, member)
+		pipe.ZAddGT(ctx, countryLeaderboardKey(country), member)
+		journalChange(ctx, pipe, userID)
+		return nil
+	})
+	return err
+}
+
+// UpdateUserInGlobalLeaderboards refreshes the level of a user who is already ranked.
+// Users who never competed in a tournament are left out, and a lower level is ignored.
+func UpdateUserInGlobalLeaderboards(ctx context.Context, userID uuid.UUID, country string, level int) error {
+	update := redis.ZAddArgs{XX: true, GT: true, Members: []redis.Z{{Score: float64(level), Member: userID.String()}}}
+	_, err := redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAddArgs(ctx, globalLeaderboardKey, update)
+		pipe.ZAddArgs(ctx, countryLeaderboardKey(country), update)
+		journalChange(ctx, pipe, userID)
+		return nil
+	})
+	return err
+}
+
+// RemoveUserFromGlobalLeaderboards removes a user from the global and country leaderboards.
+func RemoveUserFromGlobalLeaderboards(ctx context.Context, userID uuid.UUID, country string) error {
+	_, err := redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, globalLeaderboardKey, userID.String())
+		pipe.ZRem(ctx, countryLeaderboardKey(country), userID.String())
+		journalChange(ctx, pipe, userID)
+		return nil
+	})
+	return err
+}
+
+// GetGlobalLeaderboard retrieves the top users globally sorted by level.
+func GetGlobalLeaderboard(ctx context.Context, limit int) ([]LeaderboardEntry, error) {
+	return rangeWithScores(ctx, globalLeaderboardKey, limit)
+}
+
+// GetCountryLeaderboard retrieves the top users of a country sorted by level.
+func GetCountryLeaderboard(ctx context.Context, country string, limit int) ([]LeaderboardEntry, error) {
+	return rangeWithScores(ctx, countryLeaderboardKey(country), limit)
+}
+
+// GetGlobalRank returns the 1-based global rank and level of a user.
+// The boolean is false if the user is not on the leaderboard.
+func GetGlobalRank(ctx context.Context, userID uuid.UUID) (int, int, bool, error) {
+	return rankOf(ctx, globalLeaderboardKey, userID)
+}
+
+// GetCountryRank returns the 1-based rank and level of a user in their country.
+// The boolean is false if the user is not on the leaderboard.
+func GetCountryRank(ctx context.Context, userID uuid.UUID, country string) (int, int, bool, error) {
+	return rankOf(ctx, countryLeaderboardKey(country), userID)
+}
+
+// GlobalLeaderboardExists reports whether the global leaderboard has been built.
+func GlobalLeaderboardExists(ctx context.Context) (bool, error) {
+	n, err := redisClient.Exists(ctx, globalLeaderboardKey).Result()
+	return n > 0, err
+}
+
+// ReplaceGlobalLeaderboards rebuilds the global and country leaderboards from the given users.
+// The new sets are written under temporary keys and swapped in with RENAME so readers
+// never see a half built leaderboard. Country sets that no longer have users are removed.
+// Callers apply the changes journaled since they read the users again afterwards, see
+// GetGlobalLeaderboardChanges.
+func ReplaceGlobalLeaderboards(ctx context.Context, users []RankedUser) error {
+	suffix := ":rebuild:" + uuid.NewString()
+	tmpKey := func(key string) string { return key + suffix }
+
+	targets := map[string]bool{globalLeaderboardKey: true}
+	for start := 0; start < len(users); start += rebuildBatchSize {
+		end := start + rebuildBatchSize
+		if end > len(users) {
+			end = len(users)
+		}
+
+		_, err := redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, u := range users[start:end] {
+				member := redis.Z{Score: float64(u.Level), Member: u.UserID.String()}
+				countryKey := countryLeaderboardKey(u.Country)
+				targets[countryKey] = true
+				pipe.ZAdd(ctx, tmpKey(globalLeaderboardKey), member)
+				pipe.ZAdd(ctx, tmpKey(countryKey), member)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write rebuilt leaderboards: %w", err)
+		}
+	}
+
+	// Country sets that are not part of the rebuild are stale
+	var stale []string
+	iter := redisClient.Scan(ctx, 0, countryLeaderboardPrefix+"*", rebuildBatchSize).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if !targets[key] && !isRebuildKey(key) {
+			stale = append(stale, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	_, err := redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key := range targets {
+			if len(users) == 0 {
+				pipe.Del(ctx, key)
+				continue
+			}
+			pipe.Rename(ctx, tmpKey(key), key)
+		}
+		if len(stale) > 0 {
+			pipe.Del(ctx, stale...)
+		}
+		return nil
+	})
+	return err
+}
+
+// journalChange records that a user's place on the global leaderboards changed, and drops
+// journal entries older than the retention.
+func journalChange(ctx context.Context, pipe redis.Pipeliner, userID uuid.UUID) {
+	now := time.Now()
+	pipe.ZAdd(ctx, leaderboardChangesKey, redis.Z{Score: float64(now.UnixMilli()), Member: userID.String()})
+	pipe.ZRemRangeByScore(ctx, leaderboardChangesKey, "-inf", strconv.FormatInt(now.Add(-changeJournalRetention).UnixMilli(), 10))
+}
+
+// GetGlobalLeaderboardChanges returns the users whose place on the global leaderboards
+// changed since the given time.
+func GetGlobalLeaderboardChanges(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	members, err := redisClient.ZRangeByScore(ctx, leaderboardChangesKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if id, err := uuid.Parse(member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func isRebuildKey(key string) bool {
+	return strings.Contains(key, ":rebuild:")
+}
+
+// rankOf returns the 1-based rank and score of a user in a sorted set ordered by highest score.
+func rankOf(ctx context.Context, key string, userID uuid.UUID) (int, int, bool, error) {
+	pipe := redisClient.Pipeline()
+	rankCmd := pipe.ZRevRank(ctx, key, userID.String())
+	scoreCmd := pipe.ZScore(ctx, key, userID.String())
+	_, err := pipe.Exec(ctx)
+	if err == redis.Nil {
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return int(rankCmd.Val()) + 1, int(scoreCmd.Val()), true, nil
+}
+
+// RankWindow is a user's position in a sorted set with the entries around it.
+type RankWindow struct {
+	Rank  int // 1-based
+	Score int
+	Total int
+	Above []LeaderboardEntry // in rank order
+	Below []LeaderboardEntry // in rank order
+}
+
+// GetGlobalRankWindow returns the global rank of a user with up to n neighbours on each side.
+func GetGlobalRankWindow(ctx context.Context, userID uuid.UUID, n int) (*RankWindow, error) {
+	return rankWindow(ctx, globalLeaderboardKey, userID, n)
+}
+
+// GetCountryRankWindow returns the country rank of a user with up to n neighbours on each side.
+func GetCountryRankWindow(ctx context.Context, userID uuid.UUID, country string, n int) (*RankWindow, error) {
+	return rankWindow(ctx, countryLeaderboardKey(country), userID, n)
+}
+
+// rankWindow reads a user's rank and its neighbours with two pipelined round trips,
+// all operations are O(log N) so it stays fast with millions of ranked users.
+// It returns nil if the user is not in the set.
+func rankWindow(ctx context.Context, key string, userID uuid.UUID, n int) (*RankWindow, error) {
+	pipe := redisClient.Pipeline()
+	rankCmd := pipe.ZRevRank(ctx, key, userID.String())
+	scoreCmd := pipe.ZScore(ctx, key, userID.String())
+	totalCmd := pipe.ZCard(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := rankCmd.Val()
+	window := &RankWindow{
+		Rank:  int(index) + 1,
+		Score: int(scoreCmd.Val()),
+		Total: int(totalCmd.Val()),
+	}
+	if n <= 0 {
+		return window, nil
+	}
+
+	start := index - int64(n)
+	if start < 0 {
+		start = 0
+	}
+	results, err := redisClient.ZRevRangeWithScores(ctx, key, start, index+int64(n)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, z := range results {
+		member, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		entry := LeaderboardEntry{Member: member, Score: z.Score}
+		position := start + int64(i)
+		switch {
+		case position < index:
+			window.Above = append(window.Above, entry)
+		case position > index:
+			window.Below = append(window.Below, entry)
+		}
+	}
+	return window, nil
+}
+
+// MoveUserCountry moves a ranked user from one country leaderboard to another.
+// Users who are not on the global leaderboard are left out.
+func MoveUserCountry(ctx context.Context, userID uuid.UUID, oldCountry, newCountry string) error {
+	score, err := redisClient.ZScore(ctx, globalLeaderboardKey, userID.String()).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, countryLeaderboardKey(oldCountry), userID.String())
+		pipe.ZAdd(ctx, countryLeaderboardKey(newCountry), redis.Z{Score: score, Member: userID.String()})
+		journalChange(ctx, pipe, userID)
+		return nil
+	})
+	return err
+}
+
+// CountCountryPlayers returns how many ranked players each country leaderboard has,
+// read with one pipelined round trip.
+func CountCountryPlayers(ctx context.Context, codes []string) (map[string]int, error) {
+	pipe := redisClient.Pipeline()
+	cmds := make(map[string]*redis.IntCmd, len(codes))
+	for _, code := range codes {
+		cmds[code] = pipe.ZCard(ctx, countryLeaderboardKey(code))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(codes))
+	for code, cmd := range cmds {
+		counts[code] = int(cmd.Val())
+	}
+	return counts, nil
+}
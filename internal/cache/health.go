@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"context"
+	"errors"
+)
+
+// Ping checks that Redis answers.
+func Ping(ctx context.Context) error {
+	if redisClient == nil {
+		return errors.New("redis client is not initialized")
+	}
+	return redisClient.Ping(ctx).Err()
+}
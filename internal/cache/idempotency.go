@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+Clients send an Idempotency-Key header with state-changing requests so that a retry after
+a lost response does not apply the change twice. The first request reserves the key,
+and once it completes its response is stored under the key and replayed to retries.
+*/
+
+// IdempotentResponse is what is stored under an idempotency key. Fingerprint identifies the
+// request the key was first used with. Pending is set while that request is still running.
+type IdempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Pending     bool   `json:"pending,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+func idempotencyKey(scope, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s", scope, key)
+}
+
+// ReserveIdempotencyKey marks a key as used by the request with the given fingerprint for ttl.
+// If the key was already used, it returns false and what is stored under it. The stored value
+// is nil if it expired in the meantime.
+func ReserveIdempotencyKey(ctx context.Context, scope, key, fingerprint string, ttl time.Duration) (bool, *IdempotentResponse, error) {
+	pending, err := json.Marshal(IdempotentResponse{Fingerprint: fingerprint, Pending: true})
+	if err != nil {
+		return false, nil, err
+	}
+
+	reserved, err := redisClient.SetNX(ctx, idempotencyKey(scope, key), pending, ttl).Result()
+	if err != nil || reserved {
+		return reserved, nil, err
+	}
+
+	data, err := redisClient.Get(ctx, idempotencyKey(scope, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+
+	var stored IdempotentResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return false, nil, err
+	}
+	return false, &stored, nil
+}
+
+// SaveIdempotentResponse stores the response of a request under its key for ttl.
+func SaveIdempotentResponse(ctx context.Context, scope, key string, response IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return redisClient.Set(ctx, idempotencyKey(scope, key), data, ttl).Err()
+}
+
+// ReleaseIdempotencyKey frees a key so the request can be retried with it, for example after a 5xx.
+func ReleaseIdempotencyKey(ctx context.Context, scope, key string) error {
+	return redisClient.Del(ctx, idempotencyKey(scope, key)).Err()
+}
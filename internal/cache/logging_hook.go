@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis commands slower than this are logged as warnings.
+const slowCommandThreshold = 50 * time.Millisecond
+
+// loggingHook logs failed and slow Redis commands with the request ID from the context.
+type loggingHook struct{}
+
+func (loggingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			logger.ErrorContext(ctx, "redis dial failed", "addr", addr, "error", err)
+		}
+		return conn, err
+	}
+}
+
+func (loggingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		logCommand(ctx, cmd.Name(), 1, time.Since(start), err)
+		return err
+	}
+}
+
+func (loggingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		logCommand(ctx, "pipeline", len(cmds), time.Since(start), err)
+		return err
+	}
+}
+
+func logCommand(ctx context.Context, name string, count int, elapsed time.Duration, err error) {
+	switch {
+	case err != nil && !errors.Is(err, redis.Nil):
+		logger.ErrorContext(ctx, "redis command failed", "command", name, "commands", count, "duration_ms", elapsed.Milliseconds(), "error", err)
+	case elapsed > slowCommandThreshold:
+		logger.WarnContext(ctx, "slow redis command", "command", name, "commands", count, "duration_ms", elapsed.Milliseconds())
+	}
+}
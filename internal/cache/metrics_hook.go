@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"good-api/internal/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// metricsHook records the latency of every Redis command and pipeline.
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeCommand(cmd.Name(), time.Since(start), err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeCommand("pipeline", time.Since(start), err)
+		return err
+	}
+}
+
+func observeCommand(name string, elapsed time.Duration, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	metrics.RedisCommandDuration.WithLabelValues(name, metrics.Result(err)).Observe(elapsed.Seconds())
+}
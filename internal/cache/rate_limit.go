@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeTokenScript refills a token bucket for the time passed since its last use and takes
+// one token if there is one. Redis time is used so that every instance sees the same clock.
+// It returns whether a token was taken and the tokens left.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1]) or burst
+local last = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - last) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate))
+return {allowed, tostring(tokens)}
+`)
+
+// TakeToken takes a token from the bucket stored under key. The bucket holds at most burst
+// tokens and gains one every interval. It returns whether a token was available and how
+// many tokens are left.
+func TakeToken(ctx context.Context, key string, interval time.Duration, burst int) (bool, float64, error) {
+	ratePerMillisecond := float64(time.Millisecond) / float64(interval)
+	result, err := takeTokenScript.Run(ctx, redisClient, []string{"rate_limit:" + key},
+		strconv.FormatFloat(ratePerMillisecond, 'g', -1, 64), burst).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	allowed, _ := result[0].(int64)
+	left, _ := result[1].(string)
+	tokens, err := strconv.ParseFloat(left, 64)
+	if err != nil {
+		return false, 0, err
+	}
+	return allowed == 1, tokens, nil
+}
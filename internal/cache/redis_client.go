@@ -2,167 +2,98 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
-	"os"
-	"sync"
-
-	"good-api/internal/models"
-	"good-api/internal/repositories"
+	"good-api/internal/config"
+	"log/slog"
+	"net"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"gorm.io/gorm"
 )
 
-type UserLevelUpdater interface {
-	IncreaseLevel(userID uuid.UUID) error
-}
-
-var ctx = context.Background()
 var redisClient *redis.Client
+var logger = slog.Default()
+
+// InitRedis initializes the Redis client. It does not fail if Redis is down, /readyz reports it instead.
+func InitRedis(cfg config.RedisConfig, l *slog.Logger) {
+	logger = l
+
+	opts := &redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+
+		// Every command gets its own deadline, and the caller's context deadline is honoured too
+		DialTimeout:           cfg.DialTimeout,
+		ReadTimeout:           cfg.CommandTimeout,
+		WriteTimeout:          cfg.CommandTimeout,
+		PoolTimeout:           cfg.PoolTimeout,
+		ContextTimeoutEnabled: true,
+	}
+	if cfg.TLS {
+		host, _, _ := net.SplitHostPort(cfg.Addr)
+		opts.TLSConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         host,
+			InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+		}
+	}
 
-// InitRedis initializes the Redis client.
-func InitRedis() {
-	redisClient = redis.NewClient(&redis.Options{
-		Addr:     getEnv("REDIS_ADDR", "match3-redis:6379"),
-		Password: getEnv("REDIS_PASSWORD", ""),
-		DB:       0,
-	})
+	redisClient = redis.NewClient(opts)
+	redisClient.AddHook(loggingHook{})
+	redisClient.AddHook(metricsHook{})
 
-	_, err := redisClient.Ping(ctx).Result()
-	if err != nil {
-		panic(fmt.Sprintf("Failed to connect to Redis: %v", err))
+	// The client reconnects on its own, so an unreachable Redis only makes the app not ready
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		logger.Error("failed to connect to Redis", "addr", redisClient.Options().Addr, "error", err)
 	}
 }
 
 // AddUserToLeaderboard adds a user to a tournament leaderboard.
-func AddUserToLeaderboard(tournamentID uuid.UUID, userID uuid.UUID, level int) {
+func AddUserToLeaderboard(ctx context.Context, tournamentID uuid.UUID, userID uuid.UUID, level int) {
 	key := fmt.Sprintf("leaderboard:%s", tournamentID) // Each tournament has its own leaderboard.
 
-	// Log when a user is added
-	fmt.Printf("Adding user %s with level %d to leaderboard %s\n", userID, level, key)
-
-	result, err := redisClient.ZAdd(ctx, key, []redis.Z{ // Redis sorted set.
+	err := redisClient.ZAdd(ctx, key, []redis.Z{ // Redis sorted set.
 		{
 			Score:  float64(level),  // User's level determines ranking
 			Member: userID.String(), // User's ID is stored.
 		},
-	}...).Result()
-
-	if err != nil {
-		fmt.Printf("Error adding user to leaderboard: %v\n", err)
-	} else {
-		fmt.Printf("ZADD result: %v\n", result)
-	}
-}
-
-// GetTournamentLeaderboard retrieves tournament leaderboard sorted by level.
-func GetTournamentLeaderboard(tournamentID uuid.UUID, limit int) ([]string, error) {
-	return redisClient.ZRevRange(ctx, fmt.Sprintf("leaderboard:%s", tournamentID), 0, int64(limit-1)).Result()
-}
+	}...).Err()
 
-// SyncLeaderboardsToDB syncs all tournament leaderboards to the database using concurrency.
-func SyncLeaderboardsToDB(repo *repositories.TournamentRepository, userService UserLevelUpdater) {
-	tournamentKeys, err := redisClient.Keys(ctx, "leaderboard:*").Result()
 	if err != nil {
-		fmt.Println("Error fetching leaderboard keys:", err)
+		logger.ErrorContext(ctx, "failed to add user to leaderboard", "key", key, "user_id", userID, "error", err)
 		return
 	}
+	logger.DebugContext(ctx, "user added to leaderboard", "key", key, "user_id", userID, "level", level)
+}
 
-	var wg sync.WaitGroup
-
-	for _, key := range tournamentKeys {
-		wg.Add(1)
-
-		go func(tournamentKey string) {
-			defer wg.Done()
-
-			// Extract Tournament ID
-			tournamentIDStr := tournamentKey[len("leaderboard:"):]
-			tournamentID, err := uuid.Parse(tournamentIDStr)
-			if err != nil {
-				fmt.Println("Error parsing tournament ID:", err)
-				return
-			}
-
-			fmt.Printf("Processing leaderboard for tournament: %s\n", tournamentID.String())
-
-			// Fetch leaderboard from Redis
-			leaderboard, err := redisClient.ZRevRangeWithScores(ctx, tournamentKey, 0, -1).Result()
-			if err != nil {
-				fmt.Println("Error fetching leaderboard data:", err)
-				return
-			}
-
-			// Process top players using GORM ORM
-			for index, entry := range leaderboard {
-				userID, err := uuid.Parse(entry.Member.(string))
-				if err != nil {
-					fmt.Println("Skipping invalid user ID:", entry.Member)
-					continue
-				}
-
-				reward := calculateReward(index + 1)
-
-				if reward > 0 {
-					// Use GORM to update user coins
-					if err := repo.DB.Model(&models.User{}).
-						Where("id = ?", userID).
-						Update("coins", gorm.Expr("coins + ?", reward)).Error; err != nil {
-						fmt.Println("Failed to update user coins:", err)
-						continue
-					}
-
-					// Increase user level by 1 (if they placed in the top 10)
-					if index < 10 {
-						err = userService.IncreaseLevel(userID) // Dependency is injected, no import needed
-						if err != nil {
-							fmt.Println("Failed to increase user level:", err)
-						}
-					}
-				}
-			}
-
-			// Delete tournament leaderboard from Redis after syncing
-			redisClient.Del(ctx, tournamentKey)
-
-		}(key)
-	}
-
-	wg.Wait() // Wait for all Go routines to complete
+// RemoveUserFromLeaderboard removes a user from a tournament leaderboard.
+func RemoveUserFromLeaderboard(ctx context.Context, tournamentID uuid.UUID, userID uuid.UUID) error {
+	return redisClient.ZRem(ctx, fmt.Sprintf("leaderboard:%s", tournamentID), userID.String()).Err()
 }
 
-// Calculates the coin reward based on rank.
-func calculateReward(rank int) int {
-	switch {
-	case rank == 1:
-		return 5000
-	case rank == 2:
-		return 3000
-	case rank == 3:
-		return 2000
-	case rank >= 4 && rank <= 10:
-		return 1000
-	default:
-		return 0
+// Close closes the Redis client. It is called once on shutdown.
+func Close() error {
+	if redisClient == nil {
+		return nil
 	}
+	return redisClient.Close()
 }
 
-// Helper function to fetch environment variables.
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists && value != "" {
-		return value
-	}
-	return defaultValue
+// GetTournamentLeaderboard retrieves tournament leaderboard sorted by level.
+func GetTournamentLeaderboard(ctx context.Context, tournamentID uuid.UUID, limit int) ([]string, error) {
+	return redisClient.ZRevRange(ctx, fmt.Sprintf("leaderboard:%s", tournamentID), 0, int64(limit-1)).Result()
 }
 
-func DeleteTournamentLeaderboard(tournamentID uuid.UUID) {
+func DeleteTournamentLeaderboard(ctx context.Context, tournamentID uuid.UUID) {
 	key := fmt.Sprintf("leaderboard:%s", tournamentID)
 
 	err := redisClient.Del(ctx, key).Err()
 	if err != nil {
-		fmt.Println("Failed to delete tournament leaderboard from Redis: ", err)
+		logger.ErrorContext(ctx, "failed to delete tournament leaderboard from Redis", "key", key, "error", err)
 	} else {
-		fmt.Println("Tournament leaderboard deleted from Redis: ", key)
+		logger.InfoContext(ctx, "tournament leaderboard deleted from Redis", "key", key)
 	}
 }
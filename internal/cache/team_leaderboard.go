@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// LeaderboardEntry is a single member of a sorted set together with its score.
+type LeaderboardEntry struct {
+	Member string
+	Score  float64
+}
+
+func teamLeaderboardKey(tournamentID uuid.UUID) string {
+	return fmt.Sprintf("team_leaderboard:%s", tournamentID)
+}
+
+func teamContributionKey(tournamentID, teamID uuid.UUID) string {
+	return fmt.Sprintf("team_contributions:%s:%s", tournamentID, teamID)
+}
+
+// AddTeamToLeaderboard registers a team with a zero score in a team tournament.
+func AddTeamToLeaderboard(ctx context.Context, tournamentID, teamID uuid.UUID) error {
+	return redisClient.ZAddNX(ctx, teamLeaderboardKey(tournamentID), redis.Z{
+		Score:  0,
+		Member: teamID.String(),
+	}).Err()
+}
+
+// AddTeamContribution adds points to a team's score and records which member earned them.
+// Both sorted sets are updated in a single transaction so the team score always equals
+// the sum of its member contributions.
+func AddTeamContribution(ctx context.Context, tournamentID, teamID, userID uuid.UUID, points int) error {
+	_, err := redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, teamLeaderboardKey(tournamentID), float64(points), teamID.String())
+		pipe.ZIncrBy(ctx, teamContributionKey(tournamentID, teamID), float64(points), userID.String())
+		return nil
+	})
+	return err
+}
+
+// removeContributorScript removes the contribution of a member (ARGV[1]) from the contribution
+// sets KEYS[2..] and takes it off the score of the matching team ARGV[2..] in KEYS[1].
+var removeContributorScript = redis.NewScript(`
+for i = 2, #KEYS do
+	local points = redis.call("ZSCORE", KEYS[i], ARGV[1])
+	if points then
+		redis.call("ZREM", KEYS[i], ARGV[1])
+		redis.call("ZINCRBY", KEYS[1], -tonumber(points), ARGV[i])
+	end
+end
+return 0
+`)
+
+// RemoveTeamContributor removes a user's contributions to the given teams of a team tournament.
+// The points are taken off the team scores in the same script, so each team score still
+// equals the sum of its member contributions.
+func RemoveTeamContributor(ctx context.Context, tournamentID uuid.UUID, teamIDs []uuid.UUID, userID uuid.UUID) error {
+	if len(teamIDs) == 0 {
+		return nil
+	}
+	keys := []string{teamLeaderboardKey(tournamentID)}
+	args := []interface{}{userID.String()}
+	for _, teamID := range teamIDs {
+		keys = append(keys, teamContributionKey(tournamentID, teamID))
+		args = append(args, teamID.String())
+	}
+	return removeContributorScript.Run(ctx, redisClient, keys, args...).Err()
+}
+
+// GetTeamLeaderboard retrieves the teams of a team tournament sorted by score.
+func GetTeamLeaderboard(ctx context.Context, tournamentID uuid.UUID, limit int) ([]LeaderboardEntry, error) {
+	return rangeWithScores(ctx, teamLeaderboardKey(tournamentID), limit)
+}
+
+// GetTeamContributions retrieves the members of a team sorted by how much they contributed.
+func GetTeamContributions(ctx context.Context, tournamentID, teamID uuid.UUID) ([]LeaderboardEntry, error) {
+	return rangeWithScores(ctx, teamContributionKey(tournamentID, teamID), 0)
+}
+
+// DeleteTeamLeaderboard removes the team leaderboard and all contribution sets of a tournament.
+func DeleteTeamLeaderboard(ctx context.Context, tournamentID uuid.UUID, teamIDs []uuid.UUID) error {
+	keys := []string{teamLeaderboardKey(tournamentID)}
+	for _, teamID := range teamIDs {
+		keys = append(keys, teamContributionKey(tournamentID, teamID))
+	}
+	return redisClient.Del(ctx, keys...).Err()
+}
+
+// rangeWithScores reads a sorted set from the highest score down. A limit of 0 reads everything.
+func rangeWithScores(ctx context.Context, key string, limit int) ([]LeaderboardEntry, error) {
+	stop := int64(limit - 1)
+	if limit <= 0 {
+		stop = -1
+	}
+
+	results, err := redisClient.ZRevRangeWithScores(ctx, key, 0, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(results))
+	for _, z := range results {
+		member, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		entries = append(entries, LeaderboardEntry{Member: member, Score: z.Score})
+	}
+	return entries, nil
+}
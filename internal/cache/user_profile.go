@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+User profiles are cached read-through: a miss is loaded from Postgres and stored with a TTL.
+Every write to a user deletes the cached profile, so the next read loads it again.
+A read that loads a profile while a write deletes it can still cache the old row,
+which is why profiles also expire.
+*/
+
+func userProfileKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_profile:%s", userID)
+}
+
+// GetUserProfile returns the cached profile of a user. The boolean is false on a miss.
+func GetUserProfile(ctx context.Context, userID uuid.UUID) (*models.User, bool, error) {
+	data, err := redisClient.Get(ctx, userProfileKey(userID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var user models.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, false, err
+	}
+	return &user, true, nil
+}
+
+// SetUserProfile caches the profile of a user for ttl.
+func SetUserProfile(ctx context.Context, user *models.User, ttl time.Duration) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	return redisClient.Set(ctx, userProfileKey(user.ID), data, ttl).Err()
+}
+
+// InvalidateUserProfiles deletes the cached profiles of users after they changed.
+func InvalidateUserProfiles(ctx context.Context, userIDs ...uuid.UUID) error {
+	if redisClient == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = userProfileKey(userID)
+	}
+	return redisClient.Del(ctx, keys...).Err()
+}
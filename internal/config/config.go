@@ -0,0 +1,483 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config holds every setting of the server. It is loaded once at startup by Load
+// and passed to the packages that need it.
+//
+// Each setting can be set in the YAML file (yaml tag), with an environment variable (env tag)
+// and, for the most common ones, with a command line flag (flag tag).
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Log       LogConfig       `yaml:"log"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Redis     RedisConfig     `yaml:"redis"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+	Workers   WorkersConfig   `yaml:"workers"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Outbox    OutboxConfig    `yaml:"outbox"`
+	Webhooks  WebhooksConfig  `yaml:"webhooks"`
+	Privacy   PrivacyConfig   `yaml:"privacy"`
+	Game      GameConfig      `yaml:"game"`
+}
+
+type ServerConfig struct {
+	Addr            string        `yaml:"addr" env:"SERVER_ADDR" flag:"addr"`
+	RequestTimeout  time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// How long responses are kept for replay to requests retried with the same Idempotency-Key
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL"`
+	// Bearer token of the /admin routes; they reject every request while it is empty
+	AdminToken string `yaml:"admin_token" env:"ADMIN_TOKEN"`
+}
+
+// GRPCConfig configures the gRPC API, served next to the REST API on its own port.
+type GRPCConfig struct {
+	Enabled bool   `yaml:"enabled" env:"GRPC_ENABLED"`
+	Addr    string `yaml:"addr" env:"GRPC_ADDR" flag:"grpc-addr"`
+	// How often a watched leaderboard is checked for changes
+	LeaderboardPollInterval time.Duration `yaml:"leaderboard_poll_interval" env:"GRPC_LEADERBOARD_POLL_INTERVAL"`
+}
+
+type LogConfig struct {
+	Format string `yaml:"format" env:"LOG_FORMAT" flag:"log-format"`
+	Level  string `yaml:"level" env:"LOG_LEVEL" flag:"log-level"`
+}
+
+type DatabaseConfig struct {
+	Host            string        `yaml:"host" env:"DB_HOST" flag:"db-host"`
+	Port            int           `yaml:"port" env:"DB_PORT" flag:"db-port"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD"`
+	Name            string        `yaml:"name" env:"DB_NAME" flag:"db-name"`
+	SSLMode         string        `yaml:"sslmode" env:"DB_SSLMODE"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	QueryTimeout    time.Duration `yaml:"query_timeout" env:"DB_QUERY_TIMEOUT"`
+}
+
+// DSN returns the connection string of the database.
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s Timezone=UTC",
+		c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode)
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" env:"REDIS_ADDR" flag:"redis-addr"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"REDIS_DB" flag:"redis-db"`
+	PoolSize int    `yaml:"pool_size" env:"REDIS_POOL_SIZE"`
+	TLS      bool   `yaml:"tls" env:"REDIS_TLS"`
+	// Only for self-signed certificates in development
+	TLSInsecureSkipVerify bool          `yaml:"tls_insecure_skip_verify" env:"REDIS_TLS_INSECURE_SKIP_VERIFY"`
+	DialTimeout           time.Duration `yaml:"dial_timeout" env:"REDIS_DIAL_TIMEOUT"`
+	CommandTimeout        time.Duration `yaml:"command_timeout" env:"REDIS_COMMAND_TIMEOUT"`
+	PoolTimeout           time.Duration `yaml:"pool_timeout" env:"REDIS_POOL_TIMEOUT"`
+	// How long user profiles stay cached after they are read; 0 turns the cache off
+	ProfileTTL time.Duration `yaml:"profile_ttl" env:"REDIS_PROFILE_TTL"`
+}
+
+type WorkersConfig struct {
+	// How often interrupted tournament payouts are looked for
+	ResumeInterval time.Duration `yaml:"resume_interval" env:"RESUME_INTERVAL"`
+	// Whether this instance finishes the tournaments of the day at 23:59 UTC
+	SchedulerEnabled bool `yaml:"scheduler_enabled" env:"SCHEDULER_ENABLED"`
+}
+
+// OutboxConfig configures the delivery of domain events to the systems of other teams.
+// Events go to every configured sink; while none is configured they stay in the outbox.
+type OutboxConfig struct {
+	Enabled      bool          `yaml:"enabled" env:"OUTBOX_ENABLED"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL"`
+	BatchSize    int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE"`
+	// Events that failed this many times are no longer retried
+	MaxAttempts int `yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS"`
+	// Failed events are retried after BaseBackoff, doubling up to MaxBackoff
+	BaseBackoff time.Duration `yaml:"base_backoff" env:"OUTBOX_BASE_BACKOFF"`
+	MaxBackoff  time.Duration `yaml:"max_backoff" env:"OUTBOX_MAX_BACKOFF"`
+	// Events are POSTed to this URL when set
+	WebhookURL     string        `yaml:"webhook_url" env:"OUTBOX_WEBHOOK_URL"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout" env:"OUTBOX_WEBHOOK_TIMEOUT"`
+	// Events are appended as JSON lines to this file when set, "-" writes them to stdout
+	File string `yaml:"file" env:"OUTBOX_FILE"`
+}
+
+// WebhooksConfig configures the delivery of domain events to partner webhook subscriptions.
+// Failed deliveries are retried after BaseBackoff, doubling up to MaxBackoff, and moved to
+// the dead-letter table after MaxAttempts.
+type WebhooksConfig struct {
+	Enabled      bool          `yaml:"enabled" env:"WEBHOOKS_ENABLED"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL"`
+	BatchSize    int           `yaml:"batch_size" env:"WEBHOOKS_BATCH_SIZE"`
+	MaxAttempts  int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS"`
+	BaseBackoff  time.Duration `yaml:"base_backoff" env:"WEBHOOKS_BASE_BACKOFF"`
+	MaxBackoff   time.Duration `yaml:"max_backoff" env:"WEBHOOKS_MAX_BACKOFF"`
+	Timeout      time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT"`
+	// Allows http URLs and private addresses for subscriptions; for local development only
+	AllowInsecureURLs bool `yaml:"allow_insecure_urls" env:"WEBHOOKS_ALLOW_INSECURE_URLS"`
+}
+
+// PrivacyConfig configures the erasure of deleted accounts. Deleted users are hidden at once
+// and erased for good by a background job once ErasureRetention has passed.
+type PrivacyConfig struct {
+	ErasureRetention time.Duration `yaml:"erasure_retention" env:"ERASURE_RETENTION"`
+	ErasureInterval  time.Duration `yaml:"erasure_interval" env:"ERASURE_INTERVAL"`
+	ErasureBatchSize int           `yaml:"erasure_batch_size" env:"ERASURE_BATCH_SIZE"`
+}
+
+// RateLimitConfig holds the request limits of each route group. Limits apply per user,
+// or per client IP when the route does not name a user.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	// redis shares the limits between instances, memory keeps them per instance for single-node dev
+	Backend string `yaml:"backend" env:"RATE_LIMIT_BACKEND"`
+	// Requests naming a user are also limited per client IP, to IPFactor times the group's
+	// limit, so clients cannot get around the limit by naming other users. 0 turns it off.
+	IPFactor int `yaml:"ip_factor" env:"RATE_LIMIT_IP_FACTOR"`
+
+	Users       RateLimit `yaml:"users"`
+	Tournaments RateLimit `yaml:"tournaments"`
+	Scores      RateLimit `yaml:"scores"`
+	Teams       RateLimit `yaml:"teams"`
+	Friends     RateLimit `yaml:"friends"`
+	Leaderboard RateLimit `yaml:"leaderboard"`
+	Shop        RateLimit `yaml:"shop"`
+}
+
+// RateLimit is a token bucket: Requests tokens are added every Per, up to Burst.
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+// Scaled returns the limit with factor times the requests and burst, or no limit for factor 0.
+func (l RateLimit) Scaled(factor int) RateLimit {
+	if factor <= 0 {
+		return RateLimit{}
+	}
+	return RateLimit{Requests: l.Requests * factor, Per: l.Per, Burst: l.Burst * factor}
+}
+
+// Group returns the limit of a route group by its YAML name.
+func (c RateLimitConfig) Group(name string) (RateLimit, bool) {
+	limit, ok := c.groups()[name]
+	return limit, ok
+}
+
+func (c RateLimitConfig) groups() map[string]RateLimit {
+	return map[string]RateLimit{
+		"users":       c.Users,
+		"tournaments": c.Tournaments,
+		"scores":      c.Scores,
+		"teams":       c.Teams,
+		"friends":     c.Friends,
+		"leaderboard": c.Leaderboard,
+		"shop":        c.Shop,
+	}
+}
+
+// GameConfig holds the game rules.
+type GameConfig struct {
+	StartingCoins int `yaml:"starting_coins" env:"GAME_STARTING_COINS"`
+	LevelUpCoins  int `yaml:"level_up_coins" env:"GAME_LEVEL_UP_COINS"`
+
+	EntryFee        int `yaml:"entry_fee" env:"GAME_ENTRY_FEE"`
+	EntryMinLevel   int `yaml:"entry_min_level" env:"GAME_ENTRY_MIN_LEVEL"`
+	EntryCutoffHour int `yaml:"entry_cutoff_hour" env:"GAME_ENTRY_CUTOFF_HOUR"`
+	TournamentSize  int `yaml:"tournament_size" env:"GAME_TOURNAMENT_SIZE"`
+	// Coin reward per rank, starting at rank 1. Ranks past the end get nothing.
+	Rewards []int `yaml:"rewards" env:"GAME_REWARDS"`
+	// Number of top ranks that get a level-up when a tournament ends
+	LevelUpRanks int `yaml:"level_up_ranks" env:"GAME_LEVEL_UP_RANKS"`
+
+	TeamTournamentMaxTeams int `yaml:"team_tournament_max_teams" env:"GAME_TEAM_TOURNAMENT_MAX_TEAMS"`
+	// Coin reward pool per team rank, starting at rank 1
+	TeamRewards []int `yaml:"team_rewards" env:"GAME_TEAM_REWARDS"`
+
+	// Coins a username change costs
+	UsernameChangeCost int `yaml:"username_change_cost" env:"GAME_USERNAME_CHANGE_COST"`
+	// Minimum time between two username changes of a user
+	UsernameChangeCooldown time.Duration `yaml:"username_change_cooldown" env:"GAME_USERNAME_CHANGE_COOLDOWN"`
+	// How long a given up username stays held for its previous owner
+	UsernameHoldPeriod time.Duration `yaml:"username_hold_period" env:"GAME_USERNAME_HOLD_PERIOD"`
+
+	// Lives a user can have; every failed level costs one
+	MaxLives int `yaml:"max_lives" env:"GAME_MAX_LIVES"`
+	// Time it takes to get back one life, up to the cap
+	LifeRegenInterval time.Duration `yaml:"life_regen_interval" env:"GAME_LIFE_REGEN_INTERVAL"`
+	// Coins it costs to refill all lives at once
+	LivesRefillCost int `yaml:"lives_refill_cost" env:"GAME_LIVES_REFILL_COST"`
+
+	// Daily login reward calendar: coins for each day of a login streak, starting at day 1.
+	// It starts over after the last day, and a missed UTC day resets the streak.
+	DailyRewards []int `yaml:"daily_rewards" env:"GAME_DAILY_REWARDS"`
+}
+
+// Reward returns the coin reward of a rank (1-based) in a solo tournament.
+func (g GameConfig) Reward(rank int) int {
+	return rewardAt(g.Rewards, rank)
+}
+
+// DailyReward returns the coins of a daily login reward for a streak of streak days (1-based).
+func (g GameConfig) DailyReward(streak int) int {
+	if streak < 1 || len(g.DailyRewards) == 0 {
+		return 0
+	}
+	return g.DailyRewards[(streak-1)%len(g.DailyRewards)]
+}
+
+// TeamReward returns the coin reward pool of a team rank (1-based) in a team tournament.
+func (g GameConfig) TeamReward(rank int) int {
+	return rewardAt(g.TeamRewards, rank)
+}
+
+func rewardAt(rewards []int, rank int) int {
+	if rank < 1 || rank > len(rewards) {
+		return 0
+	}
+	return rewards[rank-1]
+}
+
+// EntryCutoff returns the time tournament entry closes on the day of now, in UTC.
+func (g GameConfig) EntryCutoff(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), g.EntryCutoffHour, 0, 0, 0, time.UTC)
+}
+
+// Default returns the settings used when nothing else is configured.
+// They match the docker-compose setup.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            ":8080",
+			RequestTimeout:  10 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			IdempotencyTTL:  24 * time.Hour,
+		},
+		GRPC: GRPCConfig{
+			Enabled:                 true,
+			Addr:                    ":9090",
+			LeaderboardPollInterval: time.Second,
+		},
+		Log: LogConfig{
+			Format: "json",
+			Level:  "info",
+		},
+		Database: DatabaseConfig{
+			Host:            "match3-postgres",
+			Port:            5432,
+			User:            "postgres",
+			Password:        "password",
+			Name:            "match3_db",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+		},
+		Redis: RedisConfig{
+			Addr:           "match3-redis:6379",
+			DB:             0,
+			PoolSize:       20,
+			DialTimeout:    2 * time.Second,
+			CommandTimeout: 1 * time.Second,
+			PoolTimeout:    2 * time.Second,
+			ProfileTTL:     5 * time.Minute,
+		},
+		Workers: WorkersConfig{
+			ResumeInterval:   5 * time.Minute,
+			SchedulerEnabled: true,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     true,
+			Backend:     "redis",
+			IPFactor:    10,
+			Users:       RateLimit{Requests: 60, Per: time.Minute, Burst: 20},
+			Tournaments: RateLimit{Requests: 60, Per: time.Minute, Burst: 20},
+			Scores:      RateLimit{Requests: 30, Per: time.Minute, Burst: 10},
+			Teams:       RateLimit{Requests: 60, Per: time.Minute, Burst: 20},
+			Friends:     RateLimit{Requests: 60, Per: time.Minute, Burst: 20},
+			Leaderboard: RateLimit{Requests: 120, Per: time.Minute, Burst: 30},
+			Shop:        RateLimit{Requests: 30, Per: time.Minute, Burst: 10},
+		},
+		Outbox: OutboxConfig{
+			Enabled:        true,
+			PollInterval:   2 * time.Second,
+			BatchSize:      100,
+			MaxAttempts:    10,
+			BaseBackoff:    time.Second,
+			MaxBackoff:     time.Hour,
+			WebhookTimeout: 5 * time.Second,
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:      true,
+			PollInterval: 2 * time.Second,
+			BatchSize:    50,
+			MaxAttempts:  8,
+			BaseBackoff:  10 * time.Second,
+			MaxBackoff:   time.Hour,
+			Timeout:      5 * time.Second,
+		},
+		Privacy: PrivacyConfig{
+			ErasureRetention: 30 * 24 * time.Hour,
+			ErasureInterval:  time.Hour,
+			ErasureBatchSize: 100,
+		},
+		Game: GameConfig{
+			StartingCoins:          1000,
+			LevelUpCoins:           100,
+			EntryFee:               500,
+			EntryMinLevel:          10,
+			EntryCutoffHour:        19,
+			TournamentSize:         35,
+			Rewards:                []int{5000, 3000, 2000, 1000, 1000, 1000, 1000, 1000, 1000, 1000},
+			LevelUpRanks:           10,
+			TeamTournamentMaxTeams: 20,
+			TeamRewards:            []int{20000, 12000, 8000, 4000, 4000, 4000, 4000, 4000, 4000, 4000},
+			UsernameChangeCost:     500,
+			UsernameChangeCooldown: 30 * 24 * time.Hour,
+			UsernameHoldPeriod:     14 * 24 * time.Hour,
+			MaxLives:               5,
+			LifeRegenInterval:      30 * time.Minute,
+			LivesRefillCost:        900,
+			DailyRewards:           []int{100, 150, 200, 250, 300, 400, 1000},
+		},
+	}
+}
+
+// Validate checks that the settings are usable and reports every problem at once.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("server.addr %q: %w", c.Server.Addr, err))
+	}
+	check(c.Server.RequestTimeout > 0, "server.request_timeout must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.IdempotencyTTL > 0, "server.idempotency_ttl must be positive")
+
+	if _, _, err := net.SplitHostPort(c.GRPC.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("grpc.addr %q: %w", c.GRPC.Addr, err))
+	}
+	check(c.GRPC.LeaderboardPollInterval > 0, "grpc.leaderboard_poll_interval must be positive")
+
+	check(oneOf(c.Log.Format, "json", "text"), "log.format must be json or text, got %q", c.Log.Format)
+	check(oneOf(strings.ToLower(c.Log.Level), "debug", "info", "warn", "error"), "log.level must be debug, info, warn or error, got %q", c.Log.Level)
+
+	check(c.Database.Host != "", "database.host is required")
+	check(c.Database.Port > 0 && c.Database.Port < 65536, "database.port must be between 1 and 65535, got %d", c.Database.Port)
+	check(c.Database.User != "", "database.user is required")
+	check(c.Database.Name != "", "database.name is required")
+	check(oneOf(c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full"), "database.sslmode %q is not a valid sslmode", c.Database.SSLMode)
+	check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive")
+	check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns, "database.max_idle_conns must be between 0 and max_open_conns")
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+	check(c.Database.QueryTimeout > 0, "database.query_timeout must be positive")
+
+	if _, _, err := net.SplitHostPort(c.Redis.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("redis.addr %q: %w", c.Redis.Addr, err))
+	}
+	check(c.Redis.DB >= 0 && c.Redis.DB <= 15, "redis.db must be between 0 and 15, got %d", c.Redis.DB)
+	check(c.Redis.PoolSize > 0, "redis.pool_size must be positive")
+	check(c.Redis.DialTimeout > 0, "redis.dial_timeout must be positive")
+	check(c.Redis.CommandTimeout > 0, "redis.command_timeout must be positive")
+	check(c.Redis.PoolTimeout > 0, "redis.pool_timeout must be positive")
+	check(c.Redis.ProfileTTL >= 0, "redis.profile_ttl must not be negative")
+
+	check(c.Workers.ResumeInterval > 0, "workers.resume_interval must be positive")
+
+	check(oneOf(c.RateLimit.Backend, "redis", "memory"), "rate_limit.backend must be redis or memory, got %q", c.RateLimit.Backend)
+	check(c.RateLimit.IPFactor >= 0, "rate_limit.ip_factor must not be negative")
+	groups := c.RateLimit.groups()
+	for _, name := range sortedKeys(groups) {
+		limit := groups[name]
+		check(limit.Requests > 0 && limit.Per > 0, "rate_limit.%s needs positive requests and per", name)
+		check(limit.Burst > 0, "rate_limit.%s.burst must be positive", name)
+	}
+
+	check(c.Outbox.PollInterval > 0, "outbox.poll_interval must be positive")
+	check(c.Outbox.BatchSize > 0, "outbox.batch_size must be positive")
+	check(c.Outbox.MaxAttempts > 0, "outbox.max_attempts must be positive")
+	check(c.Outbox.BaseBackoff > 0 && c.Outbox.BaseBackoff <= c.Outbox.MaxBackoff, "outbox.base_backoff must be positive and at most outbox.max_backoff")
+	check(c.Outbox.WebhookTimeout > 0, "outbox.webhook_timeout must be positive")
+	if c.Outbox.WebhookURL != "" {
+		u, err := url.Parse(c.Outbox.WebhookURL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "outbox.webhook_url must be an http or https URL, got %q", c.Outbox.WebhookURL)
+	}
+
+	check(c.Webhooks.PollInterval > 0, "webhooks.poll_interval must be positive")
+	check(c.Webhooks.BatchSize > 0, "webhooks.batch_size must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+	check(c.Webhooks.BaseBackoff > 0 && c.Webhooks.BaseBackoff <= c.Webhooks.MaxBackoff, "webhooks.base_backoff must be positive and at most webhooks.max_backoff")
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout must be positive")
+
+	check(c.Privacy.ErasureRetention >= 0, "privacy.erasure_retention must not be negative")
+	check(c.Privacy.ErasureInterval > 0, "privacy.erasure_interval must be positive")
+	check(c.Privacy.ErasureBatchSize > 0, "privacy.erasure_batch_size must be positive")
+
+	g := c.Game
+	check(g.StartingCoins >= 0, "game.starting_coins must not be negative")
+	check(g.LevelUpCoins >= 0, "game.level_up_coins must not be negative")
+	check(g.EntryFee >= 0, "game.entry_fee must not be negative")
+	check(g.EntryMinLevel >= 1, "game.entry_min_level must be at least 1")
+	check(g.EntryCutoffHour >= 0 && g.EntryCutoffHour <= 23, "game.entry_cutoff_hour must be between 0 and 23, got %d", g.EntryCutoffHour)
+	check(g.TournamentSize >= 2, "game.tournament_size must be at least 2")
+	check(len(g.Rewards) <= g.TournamentSize, "game.rewards has more ranks than game.tournament_size")
+	check(nonNegative(g.Rewards), "game.rewards must not be negative")
+	check(g.LevelUpRanks >= 0 && g.LevelUpRanks <= g.TournamentSize, "game.level_up_ranks must be between 0 and game.tournament_size")
+	check(g.TeamTournamentMaxTeams >= 2, "game.team_tournament_max_teams must be at least 2")
+	check(len(g.TeamRewards) <= g.TeamTournamentMaxTeams, "game.team_rewards has more ranks than game.team_tournament_max_teams")
+	check(nonNegative(g.TeamRewards), "game.team_rewards must not be negative")
+	check(g.UsernameChangeCost >= 0, "game.username_change_cost must not be negative")
+	check(g.UsernameChangeCooldown >= 0, "game.username_change_cooldown must not be negative")
+	check(g.UsernameHoldPeriod >= 0, "game.username_hold_period must not be negative")
+	check(g.MaxLives >= 1, "game.max_lives must be at least 1")
+	check(g.LifeRegenInterval > 0, "game.life_regen_interval must be positive")
+	check(g.LivesRefillCost >= 0, "game.lives_refill_cost must not be negative")
+	check(len(g.DailyRewards) > 0, "game.daily_rewards must have at least one day")
+	check(nonNegative(g.DailyRewards), "game.daily_rewards must not be negative")
+
+	return errors.Join(errs...)
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(groups map[string]RateLimit) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func nonNegative(values []int) bool {
+	for _, v := range values {
+		if v < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration from, in increasing order of precedence: the defaults,
+// the YAML file given by -config or CONFIG_FILE, environment variables and command line flags.
+// The result is validated before it is returned.
+func Load(args []string) (*Config, error) {
+	return load(args, os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+
+	// Flags are parsed first to find the config file, and applied last so they win
+	fs := flag.NewFlagSet("good-api", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	path := fs.String("config", "", "path to a YAML config file")
+	flagValues := make(map[string]string)
+	walk(&cfg, func(field reflect.StructField, _ reflect.Value) {
+		name := field.Tag.Get("flag")
+		if name == "" {
+			return
+		}
+		fs.Func(name, "overrides "+field.Tag.Get("env"), func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	})
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *path == "" {
+		*path, _ = lookupEnv("CONFIG_FILE")
+	}
+	if *path != "" {
+		if err := loadFile(&cfg, *path); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	walk(&cfg, func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("env")
+		if raw, ok := lookupEnv(name); ok && name != "" && raw != "" {
+			if err := setValue(value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("environment variable %s: %w", name, err))
+			}
+		}
+	})
+	walk(&cfg, func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("flag")
+		if raw, ok := flagValues[name]; ok && name != "" {
+			if err := setValue(value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", name, err))
+			}
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return &cfg, nil
+}
+
+// loadFile merges a YAML file over cfg. Settings missing from the file keep their value.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// walk calls fn for every setting of cfg, descending into the sections.
+func walk(cfg *Config, fn func(reflect.StructField, reflect.Value)) {
+	walkStruct(reflect.ValueOf(cfg).Elem(), fn)
+}
+
+func walkStruct(v reflect.Value, fn func(reflect.StructField, reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if value.Kind() == reflect.Struct && value.Type() != reflect.TypeOf(time.Duration(0)) {
+			walkStruct(value, fn)
+			continue
+		}
+		fn(field, value)
+	}
+}
+
+// setValue parses raw into a setting. Lists are comma separated.
+func setValue(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Int:
+		var list []int
+		for _, part := range strings.Split(raw, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return fmt.Errorf("%q is not a comma separated list of numbers", raw)
+			}
+			list = append(list, n)
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"good-api/internal/logging"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Redacted returns the settings keyed by their YAML names, with secrets such as passwords
+// replaced and durations written as strings. It is safe to show to operators.
+func (c Config) Redacted() map[string]interface{} {
+	return redactStruct(reflect.ValueOf(c))
+}
+
+func redactStruct(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+
+		switch {
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			out[name] = time.Duration(value.Int()).String()
+		case value.Kind() == reflect.Struct:
+			out[name] = redactStruct(value)
+		case logging.IsSecret(name):
+			if value.IsZero() {
+				out[name] = ""
+			} else {
+				out[name] = logging.Redacted
+			}
+		default:
+			out[name] = value.Interface()
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package countries
+
+/*
+Countries are stored as ISO 3166-1 alpha-2 codes so that "Turkey", "TR" and "turkey"
+all end up on the same country leaderboard.
+Normalize accepts codes, English names and a few common aliases in any case.
+*/
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Unknown is the user-assigned ISO code used when a user's country is not known.
+const Unknown = "ZZ"
+
+type Country struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// aliases maps common alternative spellings (already folded) to country codes.
+var aliases = map[string]string{
+	"turkiye":                      "TR",
+	"usa":                          "US",
+	"america":                      "US",
+	"unitedstatesofamerica":        "US",
+	"uk":                           "GB",
+	"greatbritain":                 "GB",
+	"britain":                      "GB",
+	"england":                      "GB",
+	"scotland":                     "GB",
+	"wales":                        "GB",
+	"northernireland":              "GB",
+	"korea":                        "KR",
+	"republicofkorea":              "KR",
+	"russianfederation":            "RU",
+	"holland":                      "NL",
+	"thenetherlands":               "NL",
+	"czechrepublic":                "CZ",
+	"ivorycoast":                   "CI",
+	"vatican":                      "VA",
+	"holysee":                      "VA",
+	"burma":                        "MM",
+	"swaziland":                    "SZ",
+	"macedonia":                    "MK",
+	"uae":                          "AE",
+	"democraticrepublicofcongo":    "CD",
+	"congokinshasa":                "CD",
+	"congobrazzaville":             "CG",
+	"capeverde":                    "CV",
+	"caboverde":                    "CV",
+	"unknown":                      Unknown,
+	"unkown":                       Unknown, // old column default
+	"unitedstatesvirginislands":    "VI",
+	"bosniaherzegovina":            "BA",
+	"trinidadtobago":               "TT",
+	"saotomeprincipe":              "ST",
+	"antiguabarbuda":               "AG",
+	"saintkittsnevis":              "KN",
+	"stkittsandnevis":              "KN",
+	"saintvincentthegrenadines":    "VC",
+	"stvincentandthegrenadines":    "VC",
+	"stlucia":                      "LC",
+	"palestinianterritories":       "PS",
+	"laopeoplesdemocraticrepublic": "LA",
+}
+
+var (
+	byCode = make(map[string]Country, len(list))
+	byKey  = make(map[string]string, len(list)*2+len(aliases))
+)
+
+func init() {
+	for _, c := range list {
+		byCode[c.Code] = c
+		byKey[fold(c.Code)] = c.Code
+		byKey[fold(c.Name)] = c.Code
+	}
+	for alias, code := range aliases {
+		byKey[alias] = code
+	}
+}
+
+// All returns every known country ordered by code.
+func All() []Country {
+	all := make([]Country, len(list))
+	copy(all, list)
+	return all
+}
+
+// Name returns the display name of a country code.
+func Name(code string) string {
+	if code == Unknown {
+		return "Unknown"
+	}
+	return byCode[code].Name
+}
+
+// IsValid reports whether code is a known ISO 3166-1 alpha-2 code or Unknown.
+func IsValid(code string) bool {
+	if code == Unknown {
+		return true
+	}
+	_, ok := byCode[code]
+	return ok
+}
+
+// Normalize turns a country code, name or alias into an ISO 3166-1 alpha-2 code.
+// The boolean is false if the input does not match any country.
+func Normalize(input string) (string, bool) {
+	key := fold(input)
+	if key == "" {
+		return "", false
+	}
+	code, ok := byKey[key]
+	return code, ok
+}
+
+// fold lowercases a string, strips accents of common Latin letters and drops
+// everything that is not a letter, so "Côte d'Ivoire" and "cote divoire" match.
+func fold(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch r {
+		case 'ç':
+			r = 'c'
+		case 'ğ':
+			r = 'g'
+		case 'ı', 'í', 'ì', 'î', 'ï':
+			r = 'i'
+		case 'ö', 'ó', 'ò', 'ô', 'õ':
+			r = 'o'
+		case 'ş':
+			r = 's'
+		case 'ü', 'ú', 'ù', 'û':
+			r = 'u'
+		case 'é', 'è', 'ê', 'ë':
+			r = 'e'
+		case 'á', 'à', 'â', 'ä', 'ã', 'å':
+			r = 'a'
+		case 'ñ':
+			r = 'n'
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -3,9 +3,10 @@ package database
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"time"
 
+	"good-api/internal/config"
 	"good-api/internal/models"
 
 	"gorm.io/driver/postgres"
@@ -15,61 +16,86 @@ import (
 // Global database instance
 var DB *gorm.DB
 
-func InitDB() (*gorm.DB, error) {
-	// Set default values if environment variables are missing
-	host := getEnv("DB_HOST", "match3-postgres") // Matches docker-compose service name
-	user := getEnv("DB_USER", "postgres")
-	password := getEnv("DB_PASSWORD", "password") // Match Docker Compose
-	dbname := getEnv("DB_NAME", "match3_db")
-	port := getEnv("DB_PORT", "5432") // Default PostgreSQL port
+// Number of connection attempts at startup before giving up.
+const connectAttempts = 5
 
-	fmt.Println("Connecting to database with:")
-	fmt.Printf("HOST: %s, USER: %s, PASSWORD: %s, DB: %s, PORT: %s\n", host, user, password, dbname, port)
+// InitDB connects to PostgreSQL with the pool settings of cfg and migrates the schema.
+func InitDB(cfg config.DatabaseConfig, logger *slog.Logger) (*gorm.DB, error) {
+	// The password is never logged
+	logger.Info("connecting to database", "host", cfg.Host, "user", cfg.User, "db", cfg.Name, "port", cfg.Port)
+	dsn := cfg.DSN()
 
-	// Create the DSN (Database Source Name)
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable Timezone=UTC",
-		host, user, password, dbname, port,
-	)
-
-	log.Println("Connecting to database with DSN:", dsn)
-
-	// Connect to PostgreSQL
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-		return nil, err
+	// Connect to PostgreSQL, retrying for a while in case it is still starting or restarting
+	var db *gorm.DB
+	var err error
+	wait := time.Second
+	for attempt := 1; ; attempt++ {
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: newGormLogger(logger)})
+		if err == nil {
+			break
+		}
+		if attempt == connectAttempts {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		logger.Warn("database not reachable, retrying", "attempt", attempt, "retry_in", wait.String(), "error", err)
+		time.Sleep(wait)
+		wait *= 2
 	}
 	if db == nil {
 		return nil, errors.New("database connection is nil after initialization")
 	}
 
-	log.Println("Connected to database successfully!")
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure connection pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := registerMetrics(db); err != nil {
+		return nil, fmt.Errorf("failed to register query metrics: %w", err)
+	}
+
+	logger.Info("connected to database")
 
 	// Enable uuid-ossp extension (required for generating UUIDs in postgres)
 	err = db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";").Error
 	if err != nil {
-		log.Printf("Warning: could not enable uuid-ossp extension: %v", err)
+		logger.Warn("could not enable uuid-ossp extension", "error", err)
 	}
 
 	// AutoMigrate will create the table if it does not exist
-	err = db.AutoMigrate(&models.User{}, &models.Tournament{}, &models.TournamentParticipant{})
+	err = db.AutoMigrate(&models.User{}, &models.Tournament{}, &models.TournamentParticipant{},
+		&models.Team{}, &models.TeamMember{}, &models.TeamTournamentEntry{},
+		&models.Friendship{}, &models.UserContactHash{}, &models.SchemaMigration{},
+		&models.OutboxEvent{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.WebhookDeadLetter{},
+		&models.UsernameChange{}, &models.ShopItem{}, &models.InventoryItem{}, &models.DailyRewardClaim{},
+		&models.TournamentReward{})
 	if err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	// Data migrations run after the schema is up to date
+	if err := RunMigrations(db, logger); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	log.Println("Database migration completed!")
+	logger.Info("database migration completed")
 
 	// Assign to global variable
 	DB = db
 	return db, nil
 }
 
-// Helper function to check environment variables
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists && value != "" {
-		return value
+// Close closes the connection pool opened by InitDB.
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
 	}
-	return defaultValue
+	return sqlDB.Close()
 }
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"good-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Ping checks that Postgres answers and returns the connection pool statistics.
+func Ping(ctx context.Context, db *gorm.DB) (sql.DBStats, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return sqlDB.Stats(), err
+	}
+	return sqlDB.Stats(), nil
+}
+
+// ExpectedMigrationVersion is the version of the newest migration this build knows about.
+func ExpectedMigrationVersion() string {
+	if len(migrations) == 0 {
+		return ""
+	}
+	return migrations[len(migrations)-1].version
+}
+
+// MigrationVersion returns the newest migration applied to the database. It fails if the
+// database is behind this build, for example while another instance is still migrating.
+func MigrationVersion(ctx context.Context, db *gorm.DB) (string, error) {
+	var version string
+	err := db.WithContext(ctx).Model(&models.SchemaMigration{}).
+		Select("COALESCE(MAX(version), '')").
+		Scan(&version).Error
+	if err != nil {
+		return "", err
+	}
+	if version < ExpectedMigrationVersion() {
+		return version, fmt.Errorf("database is at migration %q, expected %q", version, ExpectedMigrationVersion())
+	}
+	return version, nil
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"good-api/internal/countries"
+	"good-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+AutoMigrate only creates and alters tables. Data migrations that rewrite existing
+rows are listed here and run once, in order. Each applied version is recorded in
+the schema_migrations table.
+*/
+
+type migration struct {
+	version string
+	run     func(tx *gorm.DB) error
+	// rebuildLeaderboards is set when the migration changes data the Redis leaderboards are built from
+	rebuildLeaderboards bool
+}
+
+var migrations = []migration{
+	{version: "0001_country_iso_codes", run: backfillCountryCodes, rebuildLeaderboards: true},
+	{version: "0002_unique_usernames", run: uniqueUsernames},
+	{version: "0003_booster_catalog", run: seedBoosterCatalog},
+}
+
+// LeaderboardRebuildRequired is set when a migration applied at startup invalidated the Redis leaderboards.
+var LeaderboardRebuildRequired bool
+
+// RunMigrations applies every migration that has not been recorded yet. It is safe to run more than once.
+func RunMigrations(db *gorm.DB, logger *slog.Logger) error {
+	for _, m := range migrations {
+		var count int64
+		if err := db.Model(&models.SchemaMigration{}).Where("version = ?", m.version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&models.SchemaMigration{Version: m.version, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.version, err)
+		}
+
+		if m.rebuildLeaderboards {
+			LeaderboardRebuildRequired = true
+		}
+		logger.Info("applied migration", "version", m.version)
+	}
+	return nil
+}
+
+// backfillCountryCodes rewrites free text countries ("Turkey", "turkey", "Unkown") to ISO 3166-1 alpha-2 codes.
+// Values that do not match any country become the unknown code.
+func backfillCountryCodes(tx *gorm.DB) error {
+	var values []string
+	if err := tx.Model(&models.User{}).Distinct().Pluck("country", &values).Error; err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		code, ok := countries.Normalize(value)
+		if !ok {
+			code = countries.Unknown
+		}
+		if code == value {
+			continue
+		}
+
+		if err := tx.Model(&models.User{}).Where("country = ?", value).Update("country", code).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uniqueUsernames enforces unique usernames, ignoring case, for users that are not deleted.
+// Nothing enforced it before, so duplicates are renamed first: the highest level user keeps
+// the name and the others get a suffix from their ID.
+func uniqueUsernames(tx *gorm.DB) error {
+	err := tx.Exec(`
+		UPDATE users SET username = username || '_' || left(id::text, 8)
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, row_number() OVER (PARTITION BY lower(username) ORDER BY level DESC, id) AS n
+				FROM users WHERE deleted_at IS NULL
+			) ranked WHERE n > 1
+		)`).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users (lower(username)) WHERE deleted_at IS NULL").Error
+	if err != nil {
+		return err
+	}
+	// Looked up on every username change and new user, to find held names
+	return tx.Exec("CREATE INDEX IF NOT EXISTS idx_username_changes_old_username ON username_changes (lower(old_username), held_until)").Error
+}
+
+// seedBoosterCatalog adds the boosters to the shop catalog at their launch prices.
+// Admins change the prices from then on.
+func seedBoosterCatalog(tx *gorm.DB) error {
+	items := []models.ShopItem{
+		{ID: models.BoosterHammer, Name: "Hammer", Description: "Smashes a single tile", Price: 300, Available: true},
+		{ID: models.BoosterShuffle, Name: "Shuffle", Description: "Reshuffles the board", Price: 200, Available: true},
+		{ID: models.BoosterExtraMoves, Name: "+5 Moves", Description: "Adds five moves to a level", Price: 400, Available: true},
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&items).Error
+}
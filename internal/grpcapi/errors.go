@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"good-api/internal/handlers"
+	"good-api/internal/services"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo detail attached to every error.
+const errorDomain = "good-api"
+
+// kindCode maps domain error kinds to gRPC codes, like handlers map them to HTTP statuses.
+var kindCode = map[services.Kind]codes.Code{
+	services.NotFound:          codes.NotFound,
+	services.AlreadyEntered:    codes.AlreadyExists,
+	services.EntryClosed:       codes.FailedPrecondition,
+	services.InsufficientFunds: codes.FailedPrecondition,
+	services.Conflict:          codes.Aborted,
+	services.Invalid:           codes.InvalidArgument,
+	services.Forbidden:         codes.PermissionDenied,
+}
+
+// statusError turns err into a gRPC status the way handlers turn it into a problem response.
+// The stable error code is sent as the reason of an ErrorInfo detail, so clients can tell
+// errors with the same gRPC code apart. Details of internal errors are only logged.
+func statusError(ctx context.Context, err error) error {
+	switch domainErr := services.AsError(err); {
+	case isTimeout(ctx, err):
+		return newStatus(codes.DeadlineExceeded, handlers.CodeTimeout, "request timed out")
+	case isUnavailable(ctx, err):
+		return newStatus(codes.Unavailable, handlers.CodeUnavailable, "service unavailable, try again later")
+	case domainErr != nil:
+		code, ok := kindCode[domainErr.Kind]
+		if !ok {
+			code = codes.InvalidArgument
+		}
+		return newStatus(code, domainErr.Code, domainErr.Message)
+	default:
+		return newStatus(codes.Internal, handlers.CodeInternal, "internal server error")
+	}
+}
+
+// invalidArgument reports a malformed request.
+func invalidArgument(message string) error {
+	return newStatus(codes.InvalidArgument, handlers.CodeInvalidRequest, message)
+}
+
+// validationError reports the invalid fields of a request, with their codes as ErrorInfo metadata.
+func validationError(fields []handlers.FieldError) error {
+	st := status.New(codes.InvalidArgument, "request has invalid fields")
+	badRequest := &errdetails.BadRequest{}
+	info := &errdetails.ErrorInfo{Reason: handlers.CodeValidationFailed, Domain: errorDomain, Metadata: map[string]string{}}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		})
+		info.Metadata[field.Field] = field.Code
+	}
+	if detailed, err := st.WithDetails(info, badRequest); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func newStatus(code codes.Code, reason, message string) error {
+	st := status.New(code, message)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// isTimeout reports whether the call or one of its Postgres or Redis calls ran out of time.
+func isTimeout(ctx context.Context, err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isUnavailable reports whether the call was cancelled or a backend could not be reached.
+func isUnavailable(ctx context.Context, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -0,0 +1,453 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: goodapi/v1/leaderboards.proto
+
+package goodapiv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LeaderboardService_GetGlobalLeaderboard_FullMethodName     = "/goodapi.v1.LeaderboardService/GetGlobalLeaderboard"
+	LeaderboardService_GetCountryLeaderboard_FullMethodName    = "/goodapi.v1.LeaderboardService/GetCountryLeaderboard"
+	LeaderboardService_GetGlobalRank_FullMethodName            = "/goodapi.v1.LeaderboardService/GetGlobalRank"
+	LeaderboardService_GetCountryRank_FullMethodName           = "/goodapi.v1.LeaderboardService/GetCountryRank"
+	LeaderboardService_GetTournamentLeaderboard_FullMethodName = "/goodapi.v1.LeaderboardService/GetTournamentLeaderboard"
+	LeaderboardService_GetTournamentRank_FullMethodName        = "/goodapi.v1.LeaderboardService/GetTournamentRank"
+	LeaderboardService_GetTeamLeaderboard_FullMethodName       = "/goodapi.v1.LeaderboardService/GetTeamLeaderboard"
+	LeaderboardService_GetFriendsLeaderboard_FullMethodName    = "/goodapi.v1.LeaderboardService/GetFriendsLeaderboard"
+	LeaderboardService_WatchLeaderboard_FullMethodName         = "/goodapi.v1.LeaderboardService/WatchLeaderboard"
+)
+
+// LeaderboardServiceClient is the client API for LeaderboardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LeaderboardService mirrors the /leaderboard REST endpoints and adds live updates.
+type LeaderboardServiceClient interface {
+	// GetGlobalLeaderboard gets the top players of all countries, with the caller's row if user_id is set.
+	GetGlobalLeaderboard(ctx context.Context, in *GetGlobalLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error)
+	// GetCountryLeaderboard gets the top players of a country, with the caller's row if user_id is set.
+	GetCountryLeaderboard(ctx context.Context, in *GetCountryLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error)
+	// GetGlobalRank gets a player's global rank, percentile and neighbours.
+	GetGlobalRank(ctx context.Context, in *GetRankRequest, opts ...grpc.CallOption) (*RankInfo, error)
+	// GetCountryRank gets a player's rank, percentile and neighbours in their country.
+	GetCountryRank(ctx context.Context, in *GetRankRequest, opts ...grpc.CallOption) (*RankInfo, error)
+	// GetTournamentLeaderboard gets the players of a tournament ranked by score.
+	GetTournamentLeaderboard(ctx context.Context, in *GetTournamentLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error)
+	// GetTournamentRank gets a player's rank in a tournament.
+	GetTournamentRank(ctx context.Context, in *GetTournamentRankRequest, opts ...grpc.CallOption) (*TournamentRank, error)
+	// GetTeamLeaderboard gets the teams of a team tournament ranked by score.
+	GetTeamLeaderboard(ctx context.Context, in *GetTeamLeaderboardRequest, opts ...grpc.CallOption) (*TeamLeaderboard, error)
+	// GetFriendsLeaderboard gets a player's friends and the player ranked by level.
+	GetFriendsLeaderboard(ctx context.Context, in *GetFriendsLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error)
+	// WatchLeaderboard sends the requested leaderboard now and again every time it changes,
+	// until the client cancels the call.
+	WatchLeaderboard(ctx context.Context, in *WatchLeaderboardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LeaderboardPage], error)
+}
+
+type leaderboardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLeaderboardServiceClient(cc grpc.ClientConnInterface) LeaderboardServiceClient {
+	return &leaderboardServiceClient{cc}
+}
+
+func (c *leaderboardServiceClient) GetGlobalLeaderboard(ctx context.Context, in *GetGlobalLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaderboardPage)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetGlobalLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetCountryLeaderboard(ctx context.Context, in *GetCountryLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaderboardPage)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetCountryLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetGlobalRank(ctx context.Context, in *GetRankRequest, opts ...grpc.CallOption) (*RankInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RankInfo)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetGlobalRank_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetCountryRank(ctx context.Context, in *GetRankRequest, opts ...grpc.CallOption) (*RankInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RankInfo)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetCountryRank_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetTournamentLeaderboard(ctx context.Context, in *GetTournamentLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaderboardPage)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetTournamentLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetTournamentRank(ctx context.Context, in *GetTournamentRankRequest, opts ...grpc.CallOption) (*TournamentRank, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TournamentRank)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetTournamentRank_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetTeamLeaderboard(ctx context.Context, in *GetTeamLeaderboardRequest, opts ...grpc.CallOption) (*TeamLeaderboard, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TeamLeaderboard)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetTeamLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetFriendsLeaderboard(ctx context.Context, in *GetFriendsLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardPage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaderboardPage)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetFriendsLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) WatchLeaderboard(ctx context.Context, in *WatchLeaderboardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LeaderboardPage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LeaderboardService_ServiceDesc.Streams[0], LeaderboardService_WatchLeaderboard_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchLeaderboardRequest, LeaderboardPage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LeaderboardService_WatchLeaderboardClient = grpc.ServerStreamingClient[LeaderboardPage]
+
+// LeaderboardServiceServer is the server API for LeaderboardService service.
+// All implementations must embed UnimplementedLeaderboardServiceServer
+// for forward compatibility.
+//
+// LeaderboardService mirrors the /leaderboard REST endpoints and adds live updates.
+type LeaderboardServiceServer interface {
+	// GetGlobalLeaderboard gets the top players of all countries, with the caller's row if user_id is set.
+	GetGlobalLeaderboard(context.Context, *GetGlobalLeaderboardRequest) (*LeaderboardPage, error)
+	// GetCountryLeaderboard gets the top players of a country, with the caller's row if user_id is set.
+	GetCountryLeaderboard(context.Context, *GetCountryLeaderboardRequest) (*LeaderboardPage, error)
+	// GetGlobalRank gets a player's global rank, percentile and neighbours.
+	GetGlobalRank(context.Context, *GetRankRequest) (*RankInfo, error)
+	// GetCountryRank gets a player's rank, percentile and neighbours in their country.
+	GetCountryRank(context.Context, *GetRankRequest) (*RankInfo, error)
+	// GetTournamentLeaderboard gets the players of a tournament ranked by score.
+	GetTournamentLeaderboard(context.Context, *GetTournamentLeaderboardRequest) (*LeaderboardPage, error)
+	// GetTournamentRank gets a player's rank in a tournament.
+	GetTournamentRank(context.Context, *GetTournamentRankRequest) (*TournamentRank, error)
+	// GetTeamLeaderboard gets the teams of a team tournament ranked by score.
+	GetTeamLeaderboard(context.Context, *GetTeamLeaderboardRequest) (*TeamLeaderboard, error)
+	// GetFriendsLeaderboard gets a player's friends and the player ranked by level.
+	GetFriendsLeaderboard(context.Context, *GetFriendsLeaderboardRequest) (*LeaderboardPage, error)
+	// WatchLeaderboard sends the requested leaderboard now and again every time it changes,
+	// until the client cancels the call.
+	WatchLeaderboard(*WatchLeaderboardRequest, grpc.ServerStreamingServer[LeaderboardPage]) error
+	mustEmbedUnimplementedLeaderboardServiceServer()
+}
+
+// UnimplementedLeaderboardServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLeaderboardServiceServer struct{}
+
+func (UnimplementedLeaderboardServiceServer) GetGlobalLeaderboard(context.Context, *GetGlobalLeaderboardRequest) (*LeaderboardPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGlobalLeaderboard not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetCountryLeaderboard(context.Context, *GetCountryLeaderboardRequest) (*LeaderboardPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCountryLeaderboard not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetGlobalRank(context.Context, *GetRankRequest) (*RankInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGlobalRank not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetCountryRank(context.Context, *GetRankRequest) (*RankInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCountryRank not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetTournamentLeaderboard(context.Context, *GetTournamentLeaderboardRequest) (*LeaderboardPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTournamentLeaderboard not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetTournamentRank(context.Context, *GetTournamentRankRequest) (*TournamentRank, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTournamentRank not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetTeamLeaderboard(context.Context, *GetTeamLeaderboardRequest) (*TeamLeaderboard, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTeamLeaderboard not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetFriendsLeaderboard(context.Context, *GetFriendsLeaderboardRequest) (*LeaderboardPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFriendsLeaderboard not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) WatchLeaderboard(*WatchLeaderboardRequest, grpc.ServerStreamingServer[LeaderboardPage]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLeaderboard not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) mustEmbedUnimplementedLeaderboardServiceServer() {}
+func (UnimplementedLeaderboardServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeLeaderboardServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LeaderboardServiceServer will
+// result in compilation errors.
+type UnsafeLeaderboardServiceServer interface {
+	mustEmbedUnimplementedLeaderboardServiceServer()
+}
+
+func RegisterLeaderboardServiceServer(s grpc.ServiceRegistrar, srv LeaderboardServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLeaderboardServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LeaderboardService_ServiceDesc, srv)
+}
+
+func _LeaderboardService_GetGlobalLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGlobalLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetGlobalLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetGlobalLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetGlobalLeaderboard(ctx, req.(*GetGlobalLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetCountryLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCountryLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetCountryLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetCountryLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetCountryLeaderboard(ctx, req.(*GetCountryLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetGlobalRank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetGlobalRank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetGlobalRank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetGlobalRank(ctx, req.(*GetRankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetCountryRank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetCountryRank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetCountryRank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetCountryRank(ctx, req.(*GetRankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetTournamentLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTournamentLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetTournamentLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetTournamentLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetTournamentLeaderboard(ctx, req.(*GetTournamentLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetTournamentRank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTournamentRankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetTournamentRank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetTournamentRank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetTournamentRank(ctx, req.(*GetTournamentRankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetTeamLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeamLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetTeamLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetTeamLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetTeamLeaderboard(ctx, req.(*GetTeamLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetFriendsLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFriendsLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetFriendsLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetFriendsLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetFriendsLeaderboard(ctx, req.(*GetFriendsLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_WatchLeaderboard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLeaderboardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LeaderboardServiceServer).WatchLeaderboard(m, &grpc.GenericServerStream[WatchLeaderboardRequest, LeaderboardPage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LeaderboardService_WatchLeaderboardServer = grpc.ServerStreamingServer[LeaderboardPage]
+
+// LeaderboardService_ServiceDesc is the grpc.ServiceDesc for LeaderboardService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LeaderboardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goodapi.v1.LeaderboardService",
+	HandlerType: (*LeaderboardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetGlobalLeaderboard",
+			Handler:    _LeaderboardService_GetGlobalLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetCountryLeaderboard",
+			Handler:    _LeaderboardService_GetCountryLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetGlobalRank",
+			Handler:    _LeaderboardService_GetGlobalRank_Handler,
+		},
+		{
+			MethodName: "GetCountryRank",
+			Handler:    _LeaderboardService_GetCountryRank_Handler,
+		},
+		{
+			MethodName: "GetTournamentLeaderboard",
+			Handler:    _LeaderboardService_GetTournamentLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetTournamentRank",
+			Handler:    _LeaderboardService_GetTournamentRank_Handler,
+		},
+		{
+			MethodName: "GetTeamLeaderboard",
+			Handler:    _LeaderboardService_GetTeamLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetFriendsLeaderboard",
+			Handler:    _LeaderboardService_GetFriendsLeaderboard_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchLeaderboard",
+			Handler:       _LeaderboardService_WatchLeaderboard_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "goodapi/v1/leaderboards.proto",
+}
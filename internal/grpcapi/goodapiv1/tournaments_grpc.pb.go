@@ -0,0 +1,367 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: goodapi/v1/tournaments.proto
+
+package goodapiv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TournamentService_EnterTournament_FullMethodName      = "/goodapi.v1.TournamentService/EnterTournament"
+	TournamentService_EnterTeamTournament_FullMethodName  = "/goodapi.v1.TournamentService/EnterTeamTournament"
+	TournamentService_GetTournament_FullMethodName        = "/goodapi.v1.TournamentService/GetTournament"
+	TournamentService_ListTournaments_FullMethodName      = "/goodapi.v1.TournamentService/ListTournaments"
+	TournamentService_UpdateScore_FullMethodName          = "/goodapi.v1.TournamentService/UpdateScore"
+	TournamentService_FinishTournament_FullMethodName     = "/goodapi.v1.TournamentService/FinishTournament"
+	TournamentService_FinishAllTournaments_FullMethodName = "/goodapi.v1.TournamentService/FinishAllTournaments"
+)
+
+// TournamentServiceClient is the client API for TournamentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TournamentService mirrors the /tournaments REST endpoints.
+type TournamentServiceClient interface {
+	// EnterTournament pays the entry fee and places the user in a solo tournament with a free seat.
+	EnterTournament(ctx context.Context, in *EnterTournamentRequest, opts ...grpc.CallOption) (*Tournament, error)
+	// EnterTeamTournament enters a team into a team tournament. Only its leader or officers may.
+	EnterTeamTournament(ctx context.Context, in *EnterTeamTournamentRequest, opts ...grpc.CallOption) (*Tournament, error)
+	// GetTournament gets a tournament by ID.
+	GetTournament(ctx context.Context, in *GetTournamentRequest, opts ...grpc.CallOption) (*Tournament, error)
+	// ListTournaments lists every tournament, newest first.
+	ListTournaments(ctx context.Context, in *ListTournamentsRequest, opts ...grpc.CallOption) (*ListTournamentsResponse, error)
+	// UpdateScore counts a completed level towards the user's tournament score.
+	UpdateScore(ctx context.Context, in *UpdateScoreRequest, opts ...grpc.CallOption) (*UpdateScoreResponse, error)
+	// FinishTournament closes a tournament and pays out its rewards.
+	FinishTournament(ctx context.Context, in *FinishTournamentRequest, opts ...grpc.CallOption) (*FinishTournamentResponse, error)
+	// FinishAllTournaments closes every active tournament and pays out their rewards.
+	FinishAllTournaments(ctx context.Context, in *FinishAllTournamentsRequest, opts ...grpc.CallOption) (*FinishAllTournamentsResponse, error)
+}
+
+type tournamentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTournamentServiceClient(cc grpc.ClientConnInterface) TournamentServiceClient {
+	return &tournamentServiceClient{cc}
+}
+
+func (c *tournamentServiceClient) EnterTournament(ctx context.Context, in *EnterTournamentRequest, opts ...grpc.CallOption) (*Tournament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tournament)
+	err := c.cc.Invoke(ctx, TournamentService_EnterTournament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) EnterTeamTournament(ctx context.Context, in *EnterTeamTournamentRequest, opts ...grpc.CallOption) (*Tournament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tournament)
+	err := c.cc.Invoke(ctx, TournamentService_EnterTeamTournament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) GetTournament(ctx context.Context, in *GetTournamentRequest, opts ...grpc.CallOption) (*Tournament, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tournament)
+	err := c.cc.Invoke(ctx, TournamentService_GetTournament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) ListTournaments(ctx context.Context, in *ListTournamentsRequest, opts ...grpc.CallOption) (*ListTournamentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTournamentsResponse)
+	err := c.cc.Invoke(ctx, TournamentService_ListTournaments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) UpdateScore(ctx context.Context, in *UpdateScoreRequest, opts ...grpc.CallOption) (*UpdateScoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateScoreResponse)
+	err := c.cc.Invoke(ctx, TournamentService_UpdateScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) FinishTournament(ctx context.Context, in *FinishTournamentRequest, opts ...grpc.CallOption) (*FinishTournamentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinishTournamentResponse)
+	err := c.cc.Invoke(ctx, TournamentService_FinishTournament_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tournamentServiceClient) FinishAllTournaments(ctx context.Context, in *FinishAllTournamentsRequest, opts ...grpc.CallOption) (*FinishAllTournamentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FinishAllTournamentsResponse)
+	err := c.cc.Invoke(ctx, TournamentService_FinishAllTournaments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TournamentServiceServer is the server API for TournamentService service.
+// All implementations must embed UnimplementedTournamentServiceServer
+// for forward compatibility.
+//
+// TournamentService mirrors the /tournaments REST endpoints.
+type TournamentServiceServer interface {
+	// EnterTournament pays the entry fee and places the user in a solo tournament with a free seat.
+	EnterTournament(context.Context, *EnterTournamentRequest) (*Tournament, error)
+	// EnterTeamTournament enters a team into a team tournament. Only its leader or officers may.
+	EnterTeamTournament(context.Context, *EnterTeamTournamentRequest) (*Tournament, error)
+	// GetTournament gets a tournament by ID.
+	GetTournament(context.Context, *GetTournamentRequest) (*Tournament, error)
+	// ListTournaments lists every tournament, newest first.
+	ListTournaments(context.Context, *ListTournamentsRequest) (*ListTournamentsResponse, error)
+	// UpdateScore counts a completed level towards the user's tournament score.
+	UpdateScore(context.Context, *UpdateScoreRequest) (*UpdateScoreResponse, error)
+	// FinishTournament closes a tournament and pays out its rewards.
+	FinishTournament(context.Context, *FinishTournamentRequest) (*FinishTournamentResponse, error)
+	// FinishAllTournaments closes every active tournament and pays out their rewards.
+	FinishAllTournaments(context.Context, *FinishAllTournamentsRequest) (*FinishAllTournamentsResponse, error)
+	mustEmbedUnimplementedTournamentServiceServer()
+}
+
+// UnimplementedTournamentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTournamentServiceServer struct{}
+
+func (UnimplementedTournamentServiceServer) EnterTournament(context.Context, *EnterTournamentRequest) (*Tournament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnterTournament not implemented")
+}
+func (UnimplementedTournamentServiceServer) EnterTeamTournament(context.Context, *EnterTeamTournamentRequest) (*Tournament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnterTeamTournament not implemented")
+}
+func (UnimplementedTournamentServiceServer) GetTournament(context.Context, *GetTournamentRequest) (*Tournament, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTournament not implemented")
+}
+func (UnimplementedTournamentServiceServer) ListTournaments(context.Context, *ListTournamentsRequest) (*ListTournamentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTournaments not implemented")
+}
+func (UnimplementedTournamentServiceServer) UpdateScore(context.Context, *UpdateScoreRequest) (*UpdateScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateScore not implemented")
+}
+func (UnimplementedTournamentServiceServer) FinishTournament(context.Context, *FinishTournamentRequest) (*FinishTournamentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishTournament not implemented")
+}
+func (UnimplementedTournamentServiceServer) FinishAllTournaments(context.Context, *FinishAllTournamentsRequest) (*FinishAllTournamentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishAllTournaments not implemented")
+}
+func (UnimplementedTournamentServiceServer) mustEmbedUnimplementedTournamentServiceServer() {}
+func (UnimplementedTournamentServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeTournamentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TournamentServiceServer will
+// result in compilation errors.
+type UnsafeTournamentServiceServer interface {
+	mustEmbedUnimplementedTournamentServiceServer()
+}
+
+func RegisterTournamentServiceServer(s grpc.ServiceRegistrar, srv TournamentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTournamentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TournamentService_ServiceDesc, srv)
+}
+
+func _TournamentService_EnterTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnterTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).EnterTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_EnterTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).EnterTournament(ctx, req.(*EnterTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_EnterTeamTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnterTeamTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).EnterTeamTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_EnterTeamTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).EnterTeamTournament(ctx, req.(*EnterTeamTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_GetTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).GetTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_GetTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).GetTournament(ctx, req.(*GetTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_ListTournaments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTournamentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).ListTournaments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_ListTournaments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).ListTournaments(ctx, req.(*ListTournamentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_UpdateScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).UpdateScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_UpdateScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).UpdateScore(ctx, req.(*UpdateScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_FinishTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).FinishTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_FinishTournament_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).FinishTournament(ctx, req.(*FinishTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TournamentService_FinishAllTournaments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishAllTournamentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TournamentServiceServer).FinishAllTournaments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TournamentService_FinishAllTournaments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TournamentServiceServer).FinishAllTournaments(ctx, req.(*FinishAllTournamentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TournamentService_ServiceDesc is the grpc.ServiceDesc for TournamentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TournamentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goodapi.v1.TournamentService",
+	HandlerType: (*TournamentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "EnterTournament",
+			Handler:    _TournamentService_EnterTournament_Handler,
+		},
+		{
+			MethodName: "EnterTeamTournament",
+			Handler:    _TournamentService_EnterTeamTournament_Handler,
+		},
+		{
+			MethodName: "GetTournament",
+			Handler:    _TournamentService_GetTournament_Handler,
+		},
+		{
+			MethodName: "ListTournaments",
+			Handler:    _TournamentService_ListTournaments_Handler,
+		},
+		{
+			MethodName: "UpdateScore",
+			Handler:    _TournamentService_UpdateScore_Handler,
+		},
+		{
+			MethodName: "FinishTournament",
+			Handler:    _TournamentService_FinishTournament_Handler,
+		},
+		{
+			MethodName: "FinishAllTournaments",
+			Handler:    _TournamentService_FinishAllTournaments_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "goodapi/v1/tournaments.proto",
+}
@@ -0,0 +1,274 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"good-api/internal/countries"
+	"good-api/internal/grpcapi/goodapiv1"
+	"good-api/internal/models"
+	"good-api/internal/services"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Limits used when a request leaves them at zero, the same as the REST defaults.
+const (
+	defaultLeaderboardLimit = 1000
+	defaultTeamLimit        = 100
+)
+
+type leaderboardServer struct {
+	goodapiv1.UnimplementedLeaderboardServiceServer
+	leaderboards *services.LeaderboardService
+	pollInterval time.Duration
+}
+
+func (s *leaderboardServer) GetGlobalLeaderboard(ctx context.Context, req *goodapiv1.GetGlobalLeaderboardRequest) (*goodapiv1.LeaderboardPage, error) {
+	if err := optionalID("user ID", req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	page, err := s.leaderboards.GetGlobalLeaderboard(ctx, req.GetUserId(), limitOr(req.GetLimit(), defaultLeaderboardLimit))
+	if err != nil {
+		return nil, err
+	}
+	return newLeaderboardPage(page), nil
+}
+
+func (s *leaderboardServer) GetCountryLeaderboard(ctx context.Context, req *goodapiv1.GetCountryLeaderboardRequest) (*goodapiv1.LeaderboardPage, error) {
+	country, err := parseCountry(req.GetCountry())
+	if err != nil {
+		return nil, err
+	}
+	if err := optionalID("user ID", req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	page, err := s.leaderboards.GetCountryLeaderboard(ctx, country, req.GetUserId(), limitOr(req.GetLimit(), defaultLeaderboardLimit))
+	if err != nil {
+		return nil, err
+	}
+	return newLeaderboardPage(page), nil
+}
+
+func (s *leaderboardServer) GetGlobalRank(ctx context.Context, req *goodapiv1.GetRankRequest) (*goodapiv1.RankInfo, error) {
+	return getRank(ctx, req, s.leaderboards.GetGlobalRank)
+}
+
+func (s *leaderboardServer) GetCountryRank(ctx context.Context, req *goodapiv1.GetRankRequest) (*goodapiv1.RankInfo, error) {
+	return getRank(ctx, req, s.leaderboards.GetCountryRank)
+}
+
+// getRank handles the shared request checks of the rank calls.
+func getRank(ctx context.Context, req *goodapiv1.GetRankRequest, rankFn func(context.Context, string, int) (*models.RankInfo, error)) (*goodapiv1.RankInfo, error) {
+	if _, err := parseID("user ID", req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	info, err := rankFn(ctx, req.GetUserId(), int(req.GetNeighbours()))
+	if err != nil {
+		return nil, err
+	}
+	return &goodapiv1.RankInfo{
+		Rank:       int32(info.Rank),
+		Total:      int32(info.Total),
+		Percentile: info.Percentile,
+		Me:         newLeaderboardRow(info.Me),
+		Above:      newLeaderboardRows(info.Above),
+		Below:      newLeaderboardRows(info.Below),
+	}, nil
+}
+
+func (s *leaderboardServer) GetTournamentLeaderboard(ctx context.Context, req *goodapiv1.GetTournamentLeaderboardRequest) (*goodapiv1.LeaderboardPage, error) {
+	if _, err := parseID("tournament ID", req.GetTournamentId()); err != nil {
+		return nil, err
+	}
+	return s.tournamentLeaderboard(ctx, req.GetTournamentId(), limitOr(req.GetLimit(), defaultLeaderboardLimit))
+}
+
+// tournamentLeaderboard returns a tournament's ranked user IDs as a page. Tournament
+// leaderboards only hold user IDs, so the other fields of the rows are left empty.
+func (s *leaderboardServer) tournamentLeaderboard(ctx context.Context, tournamentID string, limit int) (*goodapiv1.LeaderboardPage, error) {
+	userIDs, err := s.leaderboards.GetTournamentLeaderboard(ctx, tournamentID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &goodapiv1.LeaderboardPage{Leaderboard: make([]*goodapiv1.LeaderboardRow, len(userIDs))}
+	for i, userID := range userIDs {
+		page.Leaderboard[i] = &goodapiv1.LeaderboardRow{Rank: int32(i + 1), UserId: userID}
+	}
+	return page, nil
+}
+
+func (s *leaderboardServer) GetTournamentRank(ctx context.Context, req *goodapiv1.GetTournamentRankRequest) (*goodapiv1.TournamentRank, error) {
+	if _, err := parseID("user ID", req.GetUserId()); err != nil {
+		return nil, err
+	}
+	if _, err := parseID("tournament ID", req.GetTournamentId()); err != nil {
+		return nil, err
+	}
+
+	rank, err := s.leaderboards.GetTournamentRank(ctx, req.GetUserId(), req.GetTournamentId())
+	if err != nil {
+		return nil, err
+	}
+	return &goodapiv1.TournamentRank{Rank: int32(rank)}, nil
+}
+
+func (s *leaderboardServer) GetTeamLeaderboard(ctx context.Context, req *goodapiv1.GetTeamLeaderboardRequest) (*goodapiv1.TeamLeaderboard, error) {
+	if _, err := parseID("tournament ID", req.GetTournamentId()); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.leaderboards.GetTeamLeaderboard(ctx, req.GetTournamentId(), limitOr(req.GetLimit(), defaultTeamLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	leaderboard := &goodapiv1.TeamLeaderboard{Teams: make([]*goodapiv1.TeamScore, len(entries))}
+	for i, entry := range entries {
+		leaderboard.Teams[i] = &goodapiv1.TeamScore{Rank: int32(i + 1), TeamId: entry.Member, Score: int64(entry.Score)}
+	}
+	return leaderboard, nil
+}
+
+func (s *leaderboardServer) GetFriendsLeaderboard(ctx context.Context, req *goodapiv1.GetFriendsLeaderboardRequest) (*goodapiv1.LeaderboardPage, error) {
+	if _, err := parseID("user ID", req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.leaderboards.GetFriendsLeaderboard(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return &goodapiv1.LeaderboardPage{Leaderboard: newLeaderboardRows(rows)}, nil
+}
+
+// WatchLeaderboard sends the requested leaderboard page right away and again whenever it changes,
+// until the client cancels the stream. Changes are found by reading the page every poll interval.
+func (s *leaderboardServer) WatchLeaderboard(req *goodapiv1.WatchLeaderboardRequest, stream goodapiv1.LeaderboardService_WatchLeaderboardServer) error {
+	fetch, err := s.watchedPage(req)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var last *goodapiv1.LeaderboardPage
+	for {
+		page, err := fetch(ctx)
+		if err != nil {
+			return err
+		}
+		if last == nil || !proto.Equal(page, last) {
+			if err := stream.Send(page); err != nil {
+				return err
+			}
+			last = page
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchedPage checks a watch request and returns the function that reads its page.
+func (s *leaderboardServer) watchedPage(req *goodapiv1.WatchLeaderboardRequest) (func(context.Context) (*goodapiv1.LeaderboardPage, error), error) {
+	if err := optionalID("user ID", req.GetUserId()); err != nil {
+		return nil, err
+	}
+	limit := limitOr(req.GetLimit(), defaultLeaderboardLimit)
+
+	switch req.GetScope() {
+	case goodapiv1.WatchLeaderboardRequest_SCOPE_GLOBAL:
+		return func(ctx context.Context) (*goodapiv1.LeaderboardPage, error) {
+			page, err := s.leaderboards.GetGlobalLeaderboard(ctx, req.GetUserId(), limit)
+			if err != nil {
+				return nil, err
+			}
+			return newLeaderboardPage(page), nil
+		}, nil
+	case goodapiv1.WatchLeaderboardRequest_SCOPE_COUNTRY:
+		country, err := parseCountry(req.GetCountry())
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (*goodapiv1.LeaderboardPage, error) {
+			page, err := s.leaderboards.GetCountryLeaderboard(ctx, country, req.GetUserId(), limit)
+			if err != nil {
+				return nil, err
+			}
+			return newLeaderboardPage(page), nil
+		}, nil
+	case goodapiv1.WatchLeaderboardRequest_SCOPE_TOURNAMENT:
+		if _, err := parseID("tournament ID", req.GetTournamentId()); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (*goodapiv1.LeaderboardPage, error) {
+			return s.tournamentLeaderboard(ctx, req.GetTournamentId(), limit)
+		}, nil
+	default:
+		return nil, invalidArgument("Scope is required")
+	}
+}
+
+// optionalID checks a UUID field that may be left empty.
+func optionalID(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := parseID(field, value)
+	return err
+}
+
+// parseCountry normalizes a required country field.
+func parseCountry(value string) (string, error) {
+	if value == "" {
+		return "", invalidArgument("Country is required")
+	}
+	country, ok := countries.Normalize(value)
+	if !ok {
+		return "", services.ErrUnknownCountry
+	}
+	return country, nil
+}
+
+func limitOr(limit int32, fallback int) int {
+	if limit == 0 {
+		return fallback
+	}
+	return int(limit)
+}
+
+func newLeaderboardPage(page *models.LeaderboardPage) *goodapiv1.LeaderboardPage {
+	resp := &goodapiv1.LeaderboardPage{Leaderboard: newLeaderboardRows(page.Leaderboard)}
+	if page.Me != nil {
+		resp.Me = newLeaderboardRow(*page.Me)
+	}
+	return resp
+}
+
+func newLeaderboardRows(rows []models.LeaderboardRow) []*goodapiv1.LeaderboardRow {
+	resp := make([]*goodapiv1.LeaderboardRow, len(rows))
+	for i, row := range rows {
+		resp[i] = newLeaderboardRow(row)
+	}
+	return resp
+}
+
+func newLeaderboardRow(row models.LeaderboardRow) *goodapiv1.LeaderboardRow {
+	return &goodapiv1.LeaderboardRow{
+		Rank:     int32(row.Rank),
+		UserId:   row.UserID.String(),
+		Username: row.Username,
+		Level:    int32(row.Level),
+		Country:  row.Country,
+	}
+}
@@ -0,0 +1,118 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"good-api/internal/config"
+	"good-api/internal/grpcapi/goodapiv1"
+	"good-api/internal/metrics"
+	"good-api/internal/ratelimit"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// serviceGroups maps the gRPC services to the rate limit groups of their REST routes.
+var serviceGroups = map[string]string{
+	"goodapi.v1.UserService":        "users",
+	"goodapi.v1.TournamentService":  "tournaments",
+	"goodapi.v1.LeaderboardService": "leaderboard",
+}
+
+// rateLimitInterceptor limits calls with the buckets of the matching REST route groups, so
+// switching APIs does not get around the limits. Calls are counted against the user they name,
+// and against the peer's IP like REST requests that name a user.
+func rateLimitInterceptor(limiter ratelimit.Limiter, cfg config.RateLimitConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !cfg.Enabled {
+			return handler(ctx, req)
+		}
+
+		service := strings.Split(strings.TrimPrefix(info.FullMethod, "/"), "/")[0]
+		groups := []string{serviceGroups[service]}
+		if info.FullMethod == goodapiv1.TournamentService_UpdateScore_FullMethodName {
+			// Like PUT /tournaments/update-score/:id, score updates also have their own limit
+			groups = append(groups, "scores")
+		}
+
+		key := callIdentity(ctx, req)
+		for _, group := range groups {
+			limit, ok := cfg.Group(group)
+			if !ok {
+				continue
+			}
+			if perIP := limit.Scaled(cfg.IPFactor); perIP.Requests > 0 && !strings.HasPrefix(key, "ip:") {
+				if err := takeToken(ctx, limiter, group, group+":"+peerIP(ctx), perIP); err != nil {
+					return nil, err
+				}
+			}
+			if err := takeToken(ctx, limiter, group, group+":"+key, limit); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// callIdentity returns the key a call is counted against, in the format of the REST identities:
+// the user the request names, or the peer's IP when it names none.
+func callIdentity(ctx context.Context, req any) string {
+	var userID string
+	switch r := req.(type) {
+	case interface{ GetUserId() string }:
+		userID = r.GetUserId()
+	case interface{ GetActorId() string }:
+		userID = r.GetActorId()
+	case *goodapiv1.GetUserRequest:
+		userID = r.GetId()
+	case *goodapiv1.UpdateUserRequest:
+		userID = r.GetId()
+	case *goodapiv1.DeleteUserRequest:
+		userID = r.GetId()
+	case *goodapiv1.IncreaseLevelRequest:
+		userID = r.GetId()
+	}
+	if userID == "" {
+		return peerIP(ctx)
+	}
+	return "user:" + userID
+}
+
+// peerIP returns the identity of the peer's IP.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:unknown"
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return "ip:" + addr
+}
+
+// takeToken takes a token from the bucket of key. When the bucket is empty, it returns
+// ResourceExhausted with the time until the next token as RetryInfo.
+func takeToken(ctx context.Context, limiter ratelimit.Limiter, group, key string, limit config.RateLimit) error {
+	result := limiter.Take(ctx, key, limit)
+	if result.Allowed {
+		return nil
+	}
+
+	metrics.RateLimited.WithLabelValues(group).Inc()
+	st := status.New(codes.ResourceExhausted, "too many requests, try again later")
+	detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: "rate_limited", Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(result.RetryAfter)},
+	)
+	if err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
@@ -0,0 +1,152 @@
+package grpcapi
+
+/*
+The gRPC API serves the same services as the REST API to game servers and internal callers.
+Every call goes through interceptors that mirror the Gin middleware: request IDs, logging,
+metrics, the request timeout, panic recovery and the rate limits of the REST route groups. Errors are mapped to gRPC codes the way
+handlers map them to HTTP statuses.
+*/
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/grpcapi/goodapiv1"
+	"good-api/internal/handlers"
+	"good-api/internal/logging"
+	"good-api/internal/metrics"
+	"good-api/internal/ratelimit"
+	"good-api/internal/repositories"
+	"good-api/internal/services"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey is the metadata key used to pass a request ID in and out of the API.
+const requestIDKey = "x-request-id"
+
+// Services holds what the gRPC services call into.
+type Services struct {
+	UserService        *services.UserService
+	UserRepo           *repositories.UserRepository
+	TournamentService  *services.TournamentService
+	TournamentRepo     *repositories.TournamentRepository
+	LeaderboardService *services.LeaderboardService
+	// Limiter is shared with the REST API, so both count against the same buckets
+	Limiter ratelimit.Limiter
+}
+
+// New creates a gRPC server with the user, tournament and leaderboard services registered.
+func New(svc Services, cfg *config.Config, logger *slog.Logger) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryInterceptor(cfg.Server.RequestTimeout, logger), rateLimitInterceptor(svc.Limiter, cfg.RateLimit)),
+		grpc.ChainStreamInterceptor(streamInterceptor(logger)),
+	)
+	goodapiv1.RegisterUserServiceServer(server, &userServer{users: svc.UserService, repo: svc.UserRepo})
+	goodapiv1.RegisterTournamentServiceServer(server, &tournamentServer{tournaments: svc.TournamentService, repo: svc.TournamentRepo})
+	goodapiv1.RegisterLeaderboardServiceServer(server, &leaderboardServer{leaderboards: svc.LeaderboardService, pollInterval: cfg.GRPC.LeaderboardPollInterval})
+	return server
+}
+
+// unaryInterceptor handles a unary call like the Gin middleware handles a request.
+func unaryInterceptor(timeout time.Duration, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		ctx = withRequestID(ctx)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				logger.ErrorContext(ctx, "panic in gRPC call", "method", info.FullMethod, "panic", r)
+				resp, err = nil, newStatus(codes.Internal, handlers.CodeInternal, "internal server error")
+			}
+			err = finish(ctx, logger, info.FullMethod, start, err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// streamInterceptor handles a streaming call. Streams are long-lived, so they get no timeout.
+func streamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := withRequestID(ss.Context())
+
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				logger.ErrorContext(ctx, "panic in gRPC stream", "method", info.FullMethod, "panic", r)
+				err = newStatus(codes.Internal, handlers.CodeInternal, "internal server error")
+			}
+			err = finish(ctx, logger, info.FullMethod, start, err)
+		}()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withRequestID stores the caller's request ID, or a new one, in ctx and sends it back in the header.
+func withRequestID(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDKey); len(values) > 0 && len(values[0]) <= 128 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+	return logging.WithRequestID(ctx, requestID)
+}
+
+// finish turns the error of a completed call into a status, logs the call and records its duration.
+// Services return plain errors, so their cause is logged here even when the client only sees Internal.
+func finish(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) error {
+	cause := err
+	if _, ok := status.FromError(err); !ok {
+		err = statusError(ctx, err)
+	}
+	code := status.Code(err)
+	duration := time.Since(start)
+	metrics.GRPCRequestDuration.WithLabelValues(method, code.String()).Observe(duration.Seconds())
+
+	level := slog.LevelInfo
+	if code == codes.Internal || code == codes.Unknown {
+		level = slog.LevelError
+	}
+	attrs := []any{
+		"method", method,
+		"code", code.String(),
+		"duration_ms", duration.Milliseconds(),
+	}
+	if cause != nil {
+		attrs = append(attrs, "error", cause)
+	}
+	logger.Log(ctx, level, "gRPC call completed", attrs...)
+	return err
+}
+
+// serverStream replaces the context of a stream with one that carries the request ID.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// parseID parses a UUID field of a request, naming the field when it is invalid.
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidArgument("Invalid " + field + " format")
+	}
+	return id, nil
+}
@@ -0,0 +1,118 @@
+package grpcapi
+
+import (
+	"context"
+
+	"good-api/internal/grpcapi/goodapiv1"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"good-api/internal/services"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type tournamentServer struct {
+	goodapiv1.UnimplementedTournamentServiceServer
+	tournaments *services.TournamentService
+	repo        *repositories.TournamentRepository
+}
+
+func (s *tournamentServer) EnterTournament(ctx context.Context, req *goodapiv1.EnterTournamentRequest) (*goodapiv1.Tournament, error) {
+	userID, err := parseID("user ID", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	tournament, err := s.tournaments.EnterTournament(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return newTournament(tournament), nil
+}
+
+func (s *tournamentServer) EnterTeamTournament(ctx context.Context, req *goodapiv1.EnterTeamTournamentRequest) (*goodapiv1.Tournament, error) {
+	teamID, err := parseID("team ID", req.GetTeamId())
+	if err != nil {
+		return nil, err
+	}
+	actorID, err := parseID("actor ID", req.GetActorId())
+	if err != nil {
+		return nil, err
+	}
+
+	tournament, err := s.tournaments.EnterTeamTournament(ctx, teamID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return newTournament(tournament), nil
+}
+
+func (s *tournamentServer) GetTournament(ctx context.Context, req *goodapiv1.GetTournamentRequest) (*goodapiv1.Tournament, error) {
+	tournamentID, err := parseID("tournament ID", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	tournament, err := s.tournaments.GetTournamentByID(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	return newTournament(tournament), nil
+}
+
+func (s *tournamentServer) ListTournaments(ctx context.Context, _ *goodapiv1.ListTournamentsRequest) (*goodapiv1.ListTournamentsResponse, error) {
+	tournaments, err := s.repo.GetAllTournaments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &goodapiv1.ListTournamentsResponse{Tournaments: make([]*goodapiv1.Tournament, len(tournaments))}
+	for i := range tournaments {
+		resp.Tournaments[i] = newTournament(&tournaments[i])
+	}
+	return resp, nil
+}
+
+func (s *tournamentServer) UpdateScore(ctx context.Context, req *goodapiv1.UpdateScoreRequest) (*goodapiv1.UpdateScoreResponse, error) {
+	userID, err := parseID("user ID", req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tournaments.UpdateScore(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &goodapiv1.UpdateScoreResponse{}, nil
+}
+
+func (s *tournamentServer) FinishTournament(ctx context.Context, req *goodapiv1.FinishTournamentRequest) (*goodapiv1.FinishTournamentResponse, error) {
+	tournamentID, err := parseID("tournament ID", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tournaments.FinishTournament(ctx, tournamentID); err != nil {
+		return nil, err
+	}
+	return &goodapiv1.FinishTournamentResponse{}, nil
+}
+
+func (s *tournamentServer) FinishAllTournaments(ctx context.Context, _ *goodapiv1.FinishAllTournamentsRequest) (*goodapiv1.FinishAllTournamentsResponse, error) {
+	if err := s.tournaments.FinishAllTournaments(ctx); err != nil {
+		return nil, err
+	}
+	return &goodapiv1.FinishAllTournamentsResponse{}, nil
+}
+
+func newTournament(tournament *models.Tournament) *goodapiv1.Tournament {
+	return &goodapiv1.Tournament{
+		Id:        tournament.ID.String(),
+		Name:      tournament.Name,
+		StartTime: timestamppb.New(tournament.StartTime),
+		EndTime:   timestamppb.New(tournament.EndTime),
+		IsActive:  tournament.IsActive,
+		UserCount: int32(tournament.UserCount),
+		MaxUsers:  int32(tournament.MaxUsers),
+		Mode:      tournament.Mode,
+	}
+}
@@ -0,0 +1,94 @@
+package grpcapi
+
+import (
+	"context"
+
+	"good-api/internal/grpcapi/goodapiv1"
+	"good-api/internal/handlers"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"good-api/internal/services"
+)
+
+type userServer struct {
+	goodapiv1.UnimplementedUserServiceServer
+	users *services.UserService
+	repo  *repositories.UserRepository
+}
+
+func (s *userServer) CreateUser(ctx context.Context, req *goodapiv1.CreateUserRequest) (*goodapiv1.User, error) {
+	// Same rules as the REST request body
+	if fields := handlers.ValidateRequest(&handlers.CreateUserRequest{Username: req.GetUsername(), Country: req.GetCountry()}); len(fields) > 0 {
+		return nil, validationError(fields)
+	}
+
+	user, err := s.users.CreateUser(ctx, &models.User{Username: req.GetUsername(), Country: req.GetCountry()})
+	if err != nil {
+		return nil, err
+	}
+	return newUser(user), nil
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *goodapiv1.GetUserRequest) (*goodapiv1.User, error) {
+	userID, err := parseID("user ID", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, services.NotFoundAs(err, services.ErrUserNotFound)
+	}
+	return newUser(user), nil
+}
+
+func (s *userServer) UpdateUser(ctx context.Context, req *goodapiv1.UpdateUserRequest) (*goodapiv1.User, error) {
+	userID, err := parseID("user ID", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	country := req.GetCountry()
+	if fields := handlers.ValidateRequest(&handlers.UpdateUserRequest{Country: &country}); len(fields) > 0 {
+		return nil, validationError(fields)
+	}
+
+	user, err := s.users.UpdateUser(ctx, userID, services.UserUpdate{Country: &country})
+	if err != nil {
+		return nil, err
+	}
+	return newUser(user), nil
+}
+
+func (s *userServer) DeleteUser(ctx context.Context, req *goodapiv1.DeleteUserRequest) (*goodapiv1.DeleteUserResponse, error) {
+	userID, err := parseID("user ID", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.DeleteUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &goodapiv1.DeleteUserResponse{}, nil
+}
+
+func (s *userServer) IncreaseLevel(ctx context.Context, req *goodapiv1.IncreaseLevelRequest) (*goodapiv1.IncreaseLevelResponse, error) {
+	userID, err := parseID("user ID", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.users.IncreaseLevel(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &goodapiv1.IncreaseLevelResponse{}, nil
+}
+
+func newUser(user *models.User) *goodapiv1.User {
+	return &goodapiv1.User{
+		Id:       user.ID.String(),
+		Username: user.Username,
+		Coins:    int32(user.Coins),
+		Level:    int32(user.Level),
+		Country:  user.Country,
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"good-api/internal/config"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	Config *config.Config
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(cfg *config.Config) *AdminHandler {
+	return &AdminHandler{Config: cfg}
+}
+
+// @Summary Show the effective configuration
+// @Description Returns the settings the server runs with, after merging the config file, environment and flags. Passwords and other secrets are redacted.
+// @Tags Admin
+// @Param Authorization header string true "Bearer admin token"
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/config [get]
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Config.Redacted())
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"good-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CountryHandler struct {
+	CountryService *services.CountryService
+}
+
+// NewCountryHandler creates a new CountryHandler.
+func NewCountryHandler(cs *services.CountryService) *CountryHandler {
+	return &CountryHandler{CountryService: cs}
+}
+
+// @Summary Get countries
+// @Description Gets the countries with their ISO code, display name and number of ranked players
+// @Tags Countries
+// @Accept json
+// @Produce json
+// @Param all query bool false "Include countries without players"
+// @Success 200 {object} []services.CountryInfo
+// @Failure 500 {object} map[string]string
+// @Router /countries [get]
+func (h *CountryHandler) GetCountries(c *gin.Context) {
+	includeEmpty := c.Query("all") == "true"
+
+	result, err := h.CountryService.GetCountries(c.Request.Context(), includeEmpty)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
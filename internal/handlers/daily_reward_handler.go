@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"good-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DailyRewardHandler struct {
+	DailyRewardService *services.DailyRewardService
+}
+
+// NewDailyRewardHandler creates a new DailyRewardHandler.
+func NewDailyRewardHandler(ds *services.DailyRewardService) *DailyRewardHandler {
+	return &DailyRewardHandler{DailyRewardService: ds}
+}
+
+// @Summary Get daily reward status
+// @Description Returns the user's login streak, whether today's reward was claimed and what the next claim pays. Days are UTC days.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.DailyRewardStatus
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /users/{id}/daily-reward [get]
+func (h *DailyRewardHandler) GetStatus(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+
+	status, err := h.DailyRewardService.GetStatus(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// @Summary Claim daily reward
+// @Description Pays the daily login reward of the current UTC day from the reward calendar. A missed day resets the streak. Claiming again on the same day pays nothing and returns the first claim with already_claimed set.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Success 200 {object} models.DailyReward
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /users/{id}/daily-reward/claim [post]
+func (h *DailyRewardHandler) Claim(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+
+	reward, err := h.DailyRewardService.Claim(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reward)
+}
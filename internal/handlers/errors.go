@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"good-api/internal/logging"
+	"good-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// Codes of errors that are not domain errors.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeTimeout        = "timeout"
+	CodeUnavailable    = "service_unavailable"
+	CodeInternal       = "internal_error"
+)
+
+// Problem is an RFC 7807 problem details response. Code is stable and machine readable,
+// so the game client can localize the message; Detail is an English fallback.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists the invalid fields of a request body that failed validation
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// kindStatus maps domain error kinds to HTTP statuses.
+var kindStatus = map[services.Kind]int{
+	services.NotFound:          http.StatusNotFound,
+	services.AlreadyEntered:    http.StatusConflict,
+	services.EntryClosed:       http.StatusForbidden,
+	services.InsufficientFunds: http.StatusPaymentRequired,
+	services.Conflict:          http.StatusConflict,
+	services.Invalid:           http.StatusBadRequest,
+	services.Forbidden:         http.StatusForbidden,
+}
+
+// respondError writes err as a problem response. Domain errors get the status of their kind
+// and their own code. Timeouts and cancelled or unreachable backends are reported as 504 and 503,
+// so clients can retry them. Anything else is a 500 whose details are only logged.
+func respondError(c *gin.Context, err error) {
+	_ = c.Error(err)
+
+	switch domainErr := services.AsError(err); {
+	case isTimeout(c, err):
+		RespondProblem(c, http.StatusGatewayTimeout, CodeTimeout, "request timed out")
+	case isUnavailable(c, err):
+		RespondProblem(c, http.StatusServiceUnavailable, CodeUnavailable, "service unavailable, try again later")
+	case domainErr != nil:
+		status, ok := kindStatus[domainErr.Kind]
+		if !ok {
+			status = http.StatusBadRequest
+		}
+		RespondProblem(c, status, domainErr.Code, domainErr.Message)
+	default:
+		RespondProblem(c, http.StatusInternalServerError, CodeInternal, "internal server error")
+	}
+}
+
+// respondInvalid writes a 400 problem response for a malformed request.
+func respondInvalid(c *gin.Context, detail string) {
+	RespondProblem(c, http.StatusBadRequest, CodeInvalidRequest, detail)
+}
+
+// RespondProblem writes a problem response with the given status and code.
+// It is exported for middleware that rejects requests before they reach a handler.
+func RespondProblem(c *gin.Context, status int, code, detail string) {
+	writeProblem(c, newProblem(c, status, code, detail))
+}
+
+func newProblem(c *gin.Context, status int, code, detail string) Problem {
+	return Problem{
+		Type:      "urn:good-api:problem:" + strings.ReplaceAll(code, "_", "-"),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: logging.RequestID(c.Request.Context()),
+	}
+}
+
+// writeProblem writes a problem response and stops the handler chain.
+func writeProblem(c *gin.Context, problem Problem) {
+	// c.JSON keeps a Content-Type that is already set
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// isTimeout reports whether the request or one of its Postgres or Redis calls ran out of time.
+// The request context is checked as well because some services replace errors with their own.
+func isTimeout(c *gin.Context, err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isUnavailable reports whether the request was cancelled or a backend could not be reached.
+func isUnavailable(c *gin.Context, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(c.Request.Context().Err(), context.Canceled) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
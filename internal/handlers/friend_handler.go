@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"good-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type FriendHandler struct {
+	FriendService *services.FriendService
+}
+
+// NewFriendHandler creates a new FriendHandler.
+func NewFriendHandler(fs *services.FriendService) *FriendHandler {
+	return &FriendHandler{FriendService: fs}
+}
+
+type friendRequest struct {
+	UserID   uuid.UUID `json:"user_id" binding:"required"`
+	FriendID uuid.UUID `json:"friend_id" binding:"required"`
+}
+
+type contactHashRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Hash   string    `json:"hash" binding:"required"`
+}
+
+type importContactsRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Hashes []string  `json:"hashes" binding:"required"`
+}
+
+// @Summary Send friend request
+// @Description Sends a friend request, accepts it right away if the other user already sent one
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/request [post]
+func (h *FriendHandler) SendRequest(c *gin.Context) {
+	var req friendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	status, err := h.FriendService.SendRequest(c.Request.Context(), req.UserID, req.FriendID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status})
+}
+
+// @Summary Accept friend request
+// @Description Accepts the friend request that friend_id sent to user_id
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/accept [post]
+func (h *FriendHandler) AcceptRequest(c *gin.Context) {
+	var req friendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	if err := h.FriendService.AcceptRequest(c.Request.Context(), req.UserID, req.FriendID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Friend request accepted"})
+}
+
+// @Summary Remove friend
+// @Description Removes a friend, or declines or cancels a pending friend request
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/remove [post]
+func (h *FriendHandler) RemoveFriend(c *gin.Context) {
+	var req friendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	if err := h.FriendService.RemoveFriend(c.Request.Context(), req.UserID, req.FriendID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Friend removed"})
+}
+
+// @Summary Block user
+// @Description Blocks a user, removing any friendship or pending request with them
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/block [post]
+func (h *FriendHandler) BlockUser(c *gin.Context) {
+	var req friendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	if err := h.FriendService.BlockUser(c.Request.Context(), req.UserID, req.FriendID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked"})
+}
+
+// @Summary Get friends
+// @Description Gets the accepted friends of the user
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} []models.User
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/{id} [get]
+func (h *FriendHandler) GetFriends(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID format")
+		return
+	}
+
+	friends, err := h.FriendService.GetFriends(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, friends)
+}
+
+// @Summary Get friend requests
+// @Description Gets the users who sent a pending friend request to the user
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} []models.User
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/{id}/requests [get]
+func (h *FriendHandler) GetIncomingRequests(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID format")
+		return
+	}
+
+	requests, err := h.FriendService.GetIncomingRequests(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Summary Set contact hash
+// @Description Registers the SHA-256 hash of the user's own device contact so friends can find them
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/contact-hash [put]
+func (h *FriendHandler) SetContactHash(c *gin.Context) {
+	var req contactHashRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	if err := h.FriendService.SetContactHash(c.Request.Context(), req.UserID, req.Hash); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contact hash saved"})
+}
+
+// @Summary Import contacts
+// @Description Sends friend requests to the users matching the hashed device contacts
+// @Tags Friends
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} handlers.Problem
+// @Router /friends/import [post]
+func (h *FriendHandler) ImportContacts(c *gin.Context) {
+	var req importContactsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	matched, err := h.FriendService.ImportContacts(c.Request.Context(), req.UserID, req.Hashes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matched_user_ids": matched})
+}
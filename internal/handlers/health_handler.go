@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"good-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	HealthService *services.HealthService
+}
+
+// NewHealthHandler creates a new HealthHandler.
+func NewHealthHandler(hs *services.HealthService) *HealthHandler {
+	return &HealthHandler{HealthService: hs}
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is running. It does not check any dependency.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// @Summary Readiness probe
+// @Description Checks the Postgres pool, Redis and the migration version. Returns 503 if any of them is down.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} services.ReadinessReport
+// @Failure 503 {object} services.ReadinessReport
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.HealthService.CheckReadiness(c.Request.Context())
+	if !report.Ready {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"good-api/internal/countries"
+	"good-api/internal/models"
 	"good-api/internal/repositories"
 	"good-api/internal/services"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type LeaderboardHandler struct {
@@ -23,45 +27,89 @@ func NewLeaderboardHandler(ls *services.LeaderboardService, lr *repositories.Lea
 }
 
 // @Summary Get Global Leaderboard
-// @Description It gets the top 1000 users from the global leaderboard
+// @Description It gets the top 1000 users from the global leaderboard, with the caller's own rank if user_id is given
 // @Tags Leaderboards
 // @Accept json
 // @Produce json
-// @Success 201 {object} []models.User
-// @Failure 400 {object} map[string]string
-// @Router /leaderboard/ [get]
+// @Success 200 {object} models.LeaderboardPage
+// @Failure 400 {object} handlers.Problem
+// @Router /leaderboard/global [get]
 func (h *LeaderboardHandler) GetGlobalLeaderboard(c *gin.Context) {
-	leaderboard, err := h.LeaderboardRepository.GetGlobalLeaderboard()
+	if !validOptionalUserID(c) {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "1000"))
+	if err != nil {
+		respondInvalid(c, "Invalid limit value")
+		return
+	}
+
+	leaderboard, err := h.LeaderboardService.GetGlobalLeaderboard(c.Request.Context(), c.Query("user_id"), limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, leaderboard)
-	// GetGlobalLeaderboard handles GET /leaderboard/global?limit=1000
+	// GetGlobalLeaderboard handles GET /leaderboard/global?user_id=xyz&limit=1000
 }
 
 // @Summary Get Country Leaderboard
-// @Description It gets the top users from the specified country
+// @Description It gets the top users from the specified country, with the caller's own rank if user_id is given
 // @Tags Leaderboards
 // @Accept json
 // @Produce json
-// @Success 201 {object} []models.User
-// @Failure 400 {object} map[string]string
-// @Router /leaderboard/ [get]
+// @Success 200 {object} models.LeaderboardPage
+// @Failure 400 {object} handlers.Problem
+// @Router /leaderboard/country [get]
 func (h *LeaderboardHandler) GetCountryLeaderboard(c *gin.Context) {
-	country := c.Query("country")
-	if country == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Country is required"})
+	if c.Query("country") == "" {
+		respondInvalid(c, "Country is required")
 		return
 	}
 
-	leaderboard, err := h.LeaderboardRepository.GetCountryLeaderboard(country)
+	country, ok := countries.Normalize(c.Query("country"))
+	if !ok {
+		respondError(c, services.ErrUnknownCountry)
+		return
+	}
+	if !validOptionalUserID(c) {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "1000"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondInvalid(c, "Invalid limit value")
+		return
+	}
+
+	leaderboard, err := h.LeaderboardService.GetCountryLeaderboard(c.Request.Context(), country, c.Query("user_id"), limit)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, leaderboard)
-	// GetCountryLeaderboard handles GET /leaderboard/country?country=Turkey&limit=1000
+	// GetCountryLeaderboard handles GET /leaderboard/country?country=TR&user_id=xyz&limit=1000
+}
+
+// @Summary Rebuild Leaderboards
+// @Description It rebuilds the global and country leaderboards in Redis from the database
+// @Tags Leaderboards
+// @Param Authorization header string true "Bearer admin token"
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} handlers.Problem
+// @Failure 500 {object} map[string]string
+// @Router /admin/leaderboard/rebuild [post]
+func (h *LeaderboardHandler) RebuildLeaderboards(c *gin.Context) {
+	count, err := h.LeaderboardService.RebuildLeaderboards(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Leaderboards rebuilt successfully", "ranked_users": count})
 }
 
 // @Summary Get Tourmament Leaderboard
@@ -70,25 +118,25 @@ func (h *LeaderboardHandler) GetCountryLeaderboard(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 201 {object} []models.User
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
 // @Router /leaderboard/ [get]
 func (h *LeaderboardHandler) GetTournamentLeaderboard(c *gin.Context) {
 	tournamentIDParam := c.Query("tournament_id")
-	if tournamentIDParam == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Tournament ID is required"})
+	if _, err := uuid.Parse(tournamentIDParam); err != nil {
+		respondInvalid(c, "Valid tournament ID is required")
 		return
 	}
 
 	limitParam := c.DefaultQuery("limit", "1000")
 	limit, err := strconv.Atoi(limitParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit value"})
+		respondInvalid(c, "Invalid limit value")
 		return
 	}
 
-	leaderboard, err := h.LeaderboardService.GetTournamentLeaderboard(tournamentIDParam, limit)
+	leaderboard, err := h.LeaderboardService.GetTournamentLeaderboard(c.Request.Context(), tournamentIDParam, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -102,22 +150,152 @@ func (h *LeaderboardHandler) GetTournamentLeaderboard(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 201 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
 // @Router /leaderboard/ [get]
 func (h *LeaderboardHandler) GetTournamentRank(c *gin.Context) {
 	userIDParam := c.Query("user_id")
 	tournamentIDParam := c.Query("tournament_id")
-	if userIDParam == "" || tournamentIDParam == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID and Tournament ID are required"})
+	if _, err := uuid.Parse(userIDParam); err != nil {
+		respondInvalid(c, "Valid user ID is required")
+		return
+	}
+	if _, err := uuid.Parse(tournamentIDParam); err != nil {
+		respondInvalid(c, "Valid tournament ID is required")
 		return
 	}
 
-	rank, err := h.LeaderboardService.GetTournamentRank(userIDParam, tournamentIDParam)
+	rank, err := h.LeaderboardService.GetTournamentRank(c.Request.Context(), userIDParam, tournamentIDParam)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"rank": rank})
 	// GetTournamentRank handles GET /leaderboard/tournament/rank?user_id=xyz&tournament_id=xyz
 }
+
+// @Summary Get Team Tournament Leaderboard
+// @Description It gets the teams of the specified team tournament ranked by score
+// @Tags Leaderboards
+// @Accept json
+// @Produce json
+// @Success 200 {object} []map[string]interface{}
+// @Failure 400 {object} handlers.Problem
+// @Router /leaderboard/team [get]
+func (h *LeaderboardHandler) GetTeamLeaderboard(c *gin.Context) {
+	tournamentIDParam := c.Query("tournament_id")
+	if _, err := uuid.Parse(tournamentIDParam); err != nil {
+		respondInvalid(c, "Valid tournament ID is required")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		respondInvalid(c, "Invalid limit value")
+		return
+	}
+
+	entries, err := h.LeaderboardService.GetTeamLeaderboard(c.Request.Context(), tournamentIDParam, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	leaderboard := make([]gin.H, 0, len(entries))
+	for i, entry := range entries {
+		leaderboard = append(leaderboard, gin.H{
+			"rank":    i + 1,
+			"team_id": entry.Member,
+			"score":   int(entry.Score),
+		})
+	}
+	c.JSON(http.StatusOK, leaderboard)
+	// GetTeamLeaderboard handles GET /leaderboard/team?tournament_id=xyz&limit=100
+}
+
+// @Summary Get Friends Leaderboard
+// @Description It ranks the user's friends by level, the user is included in the ranking
+// @Tags Leaderboards
+// @Accept json
+// @Produce json
+// @Success 200 {object} []models.LeaderboardRow
+// @Failure 400 {object} handlers.Problem
+// @Router /leaderboard/friends [get]
+func (h *LeaderboardHandler) GetFriendsLeaderboard(c *gin.Context) {
+	userIDParam := c.Query("user_id")
+	if _, err := uuid.Parse(userIDParam); err != nil {
+		respondInvalid(c, "Valid user ID is required")
+		return
+	}
+
+	leaderboard, err := h.LeaderboardService.GetFriendsLeaderboard(c.Request.Context(), userIDParam)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+	// GetFriendsLeaderboard handles GET /leaderboard/friends?user_id=xyz
+}
+
+// @Summary Get Global Rank
+// @Description It gets the user's global rank and percentile with the players directly above and below
+// @Tags Leaderboards
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.RankInfo
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} map[string]string
+// @Router /leaderboard/global/rank [get]
+func (h *LeaderboardHandler) GetGlobalRank(c *gin.Context) {
+	h.getRank(c, h.LeaderboardService.GetGlobalRank)
+	// GetGlobalRank handles GET /leaderboard/global/rank?user_id=xyz&neighbours=1
+}
+
+// @Summary Get Country Rank
+// @Description It gets the user's rank and percentile in their country with the players directly above and below
+// @Tags Leaderboards
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.RankInfo
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} map[string]string
+// @Router /leaderboard/country/rank [get]
+func (h *LeaderboardHandler) GetCountryRank(c *gin.Context) {
+	h.getRank(c, h.LeaderboardService.GetCountryRank)
+	// GetCountryRank handles GET /leaderboard/country/rank?user_id=xyz&neighbours=1
+}
+
+// getRank handles the shared query parsing and error mapping of the rank endpoints.
+func (h *LeaderboardHandler) getRank(c *gin.Context, rankFn func(context.Context, string, int) (*models.RankInfo, error)) {
+	userIDParam := c.Query("user_id")
+	if _, err := uuid.Parse(userIDParam); err != nil {
+		respondInvalid(c, "Valid user ID is required")
+		return
+	}
+
+	neighbours, err := strconv.Atoi(c.DefaultQuery("neighbours", "1"))
+	if err != nil {
+		respondInvalid(c, "Invalid neighbours value")
+		return
+	}
+
+	info, err := rankFn(c.Request.Context(), userIDParam, neighbours)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// validOptionalUserID rejects a user_id query parameter that is given but is not a UUID.
+func validOptionalUserID(c *gin.Context) bool {
+	if userID := c.Query("user_id"); userID != "" {
+		if _, err := uuid.Parse(userID); err != nil {
+			respondInvalid(c, "Valid user ID is required")
+			return false
+		}
+	}
+	return true
+}
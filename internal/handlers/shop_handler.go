@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"good-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ShopHandler struct {
+	ShopService *services.ShopService
+}
+
+// NewShopHandler creates a new ShopHandler.
+func NewShopHandler(ss *services.ShopService) *ShopHandler {
+	return &ShopHandler{ShopService: ss}
+}
+
+// PurchaseRequest is the body of POST /shop/purchase.
+type PurchaseRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	ItemID string `json:"item_id" binding:"required" example:"hammer"`
+	// Defaults to 1
+	Quantity int `json:"quantity" binding:"omitempty,min=1,max=99" example:"3"`
+}
+
+// ConsumeItemRequest is the body of POST /users/{id}/inventory/consume.
+type ConsumeItemRequest struct {
+	ItemID string `json:"item_id" binding:"required" example:"hammer"`
+	// Defaults to 1
+	Quantity int `json:"quantity" binding:"omitempty,min=1,max=99" example:"1"`
+}
+
+// UpdateShopItemRequest is the body of PUT /admin/shop/items/{id}. Left out fields are not changed.
+type UpdateShopItemRequest struct {
+	Price     *int  `json:"price" binding:"omitempty,min=0" example:"250"`
+	Available *bool `json:"available" example:"true"`
+}
+
+// @Summary List shop items
+// @Description Returns the shop catalog with the current prices. Items that are not for sale have available set to false.
+// @Tags Shop
+// @Produce json
+// @Success 200 {object} []models.ShopItem
+// @Router /shop/items [get]
+func (h *ShopHandler) GetItems(c *gin.Context) {
+	items, err := h.ShopService.GetItems(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Buy a shop item
+// @Description Buys boosters with the user's coins at the current price. The coins are paid and the boosters added to the inventory together.
+// @Tags Shop
+// @Accept json
+// @Produce json
+// @Param purchase body PurchaseRequest true "Item to buy"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Success 200 {object} models.Purchase
+// @Failure 400 {object} handlers.Problem
+// @Failure 402 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Failure 409 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
+// @Router /shop/purchase [post]
+func (h *ShopHandler) Purchase(c *gin.Context) {
+	var req PurchaseRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+
+	purchase, err := h.ShopService.Purchase(c.Request.Context(), userID, req.ItemID, quantityOrOne(req.Quantity))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, purchase)
+}
+
+// @Summary Get inventory
+// @Description Returns the boosters the user owns.
+// @Tags Shop
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} []models.InventoryItem
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /users/{id}/inventory [get]
+func (h *ShopHandler) GetInventory(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+
+	items, err := h.ShopService.GetInventory(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Use an inventory item
+// @Description Uses boosters from the user's inventory, for example when they are played in a level.
+// @Tags Shop
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param item body ConsumeItemRequest true "Item to use"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Success 200 {object} models.InventoryItem
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Failure 409 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
+// @Router /users/{id}/inventory/consume [post]
+func (h *ShopHandler) ConsumeItem(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+	var req ConsumeItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	item, err := h.ShopService.ConsumeItem(c.Request.Context(), userID, req.ItemID, quantityOrOne(req.Quantity))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Update shop item
+// @Description Changes the price or availability of a shop item. Purchases already made keep the price they were made at. Requires the admin token.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param Authorization header string true "Bearer admin token"
+// @Param item body UpdateShopItemRequest true "Changed fields"
+// @Success 200 {object} models.ShopItem
+// @Failure 400 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
+// @Router /admin/shop/items/{id} [put]
+func (h *ShopHandler) UpdateItem(c *gin.Context) {
+	var req UpdateShopItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	item, err := h.ShopService.UpdateItem(c.Request.Context(), c.Param("id"), services.ShopItemUpdate{Price: req.Price, Available: req.Available})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// quantityOrOne returns the quantity of a request, which is 1 when it is left out.
+func quantityOrOne(quantity int) int {
+	if quantity == 0 {
+		return 1
+	}
+	return quantity
+}
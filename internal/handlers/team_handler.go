@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"good-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TeamHandler struct {
+	TeamService *services.TeamService
+}
+
+// NewTeamHandler creates a new TeamHandler.
+func NewTeamHandler(ts *services.TeamService) *TeamHandler {
+	return &TeamHandler{TeamService: ts}
+}
+
+type createTeamRequest struct {
+	UserID      uuid.UUID `json:"user_id" binding:"required"`
+	Name        string    `json:"name" binding:"required"`
+	Description string    `json:"description"`
+}
+
+type teamMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+}
+
+type teamActionRequest struct {
+	ActorID uuid.UUID `json:"actor_id" binding:"required"`
+	UserID  uuid.UUID `json:"user_id" binding:"required"`
+	Role    string    `json:"role"`
+}
+
+// @Summary Create team
+// @Description Creates a new team with the user as its leader
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Team
+// @Failure 400 {object} handlers.Problem
+// @Router /teams/ [post]
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	var req createTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	team, err := h.TeamService.CreateTeam(c.Request.Context(), req.UserID, req.Name, req.Description)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, team)
+}
+
+// @Summary Get team
+// @Description Gets a single team
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Team
+// @Failure 404 {object} map[string]string
+// @Router /teams/{id} [get]
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	teamID, ok := parseTeamID(c)
+	if !ok {
+		return
+	}
+
+	team, err := h.TeamService.GetTeam(c.Request.Context(), teamID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Get team members
+// @Description Gets the members of a team with their roles
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} []models.TeamMember
+// @Failure 404 {object} map[string]string
+// @Router /teams/{id}/members [get]
+func (h *TeamHandler) GetMembers(c *gin.Context) {
+	teamID, ok := parseTeamID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.TeamService.GetMembers(c.Request.Context(), teamID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Join team
+// @Description Adds the user to the team as a member
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TeamMember
+// @Failure 400 {object} handlers.Problem
+// @Router /teams/{id}/join [post]
+func (h *TeamHandler) JoinTeam(c *gin.Context) {
+	teamID, ok := parseTeamID(c)
+	if !ok {
+		return
+	}
+
+	var req teamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	member, err := h.TeamService.JoinTeam(c.Request.Context(), teamID, req.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Leave team
+// @Description Removes the user from the team
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /teams/{id}/leave [post]
+func (h *TeamHandler) LeaveTeam(c *gin.Context) {
+	teamID, ok := parseTeamID(c)
+	if !ok {
+		return
+	}
+
+	var req teamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	if err := h.TeamService.LeaveTeam(c.Request.Context(), teamID, req.UserID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User left the team"})
+}
+
+// @Summary Kick team member
+// @Description Removes another member from the team
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /teams/{id}/kick [post]
+func (h *TeamHandler) KickMember(c *gin.Context) {
+	teamID, ok := parseTeamID(c)
+	if !ok {
+		return
+	}
+
+	var req teamActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	if err := h.TeamService.KickMember(c.Request.Context(), teamID, req.ActorID, req.UserID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member kicked from the team"})
+}
+
+// @Summary Set member role
+// @Description Changes the role of a team member
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Router /teams/{id}/role [put]
+func (h *TeamHandler) SetMemberRole(c *gin.Context) {
+	teamID, ok := parseTeamID(c)
+	if !ok {
+		return
+	}
+
+	var req teamActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	if err := h.TeamService.SetMemberRole(c.Request.Context(), teamID, req.ActorID, req.UserID, req.Role); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated"})
+}
+
+// parseTeamID reads the team ID from the path and writes a 400 response if it is malformed.
+func parseTeamID(c *gin.Context) (uuid.UUID, bool) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid team ID format")
+		return uuid.Nil, false
+	}
+	return teamID, true
+}
@@ -29,19 +29,20 @@ func NewTournamentHandler(ts *services.TournamentService, tr *repositories.Tourn
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
 // @Router /tournaments/ [post]
 func (h *TournamentHandler) EnterTournament(c *gin.Context) {
 	userIDStr := c.Param("id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondInvalid(c, "Invalid user ID format")
 		return
 	}
 
-	tournament, err := h.TournamentService.EnterTournament(userID)
+	tournament, err := h.TournamentService.EnterTournament(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -60,12 +61,12 @@ func (h *TournamentHandler) EnterTournament(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
 // @Router /tournaments/ [get]
 func (h *TournamentHandler) GetAllTournaments(c *gin.Context) {
-	tournaments, err := h.TournamentRepo.GetAllTournaments()
+	tournaments, err := h.TournamentRepo.GetAllTournaments(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tournaments"})
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"tournaments": tournaments})
@@ -77,18 +78,18 @@ func (h *TournamentHandler) GetAllTournaments(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
 // @Router /tournaments/ [get]
 func (h *TournamentHandler) GetTournament(c *gin.Context) {
 	tournamentIDStr := c.Param("id")
 	tournamentID, err := uuid.Parse(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID format"})
+		respondInvalid(c, "Invalid tournament ID format")
 		return
 	}
-	tournament, err := h.TournamentService.GetTournamentByID(tournamentID)
+	tournament, err := h.TournamentService.GetTournamentByID(c.Request.Context(), tournamentID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+		respondError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, tournament)
@@ -100,19 +101,19 @@ func (h *TournamentHandler) GetTournament(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
 // @Router /tournaments/ [post]
 func (h *TournamentHandler) FinishTournament(c *gin.Context) {
 	tournamentIDStr := c.Param("id")
 	tournamentID, err := uuid.Parse(tournamentIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID format"})
+		respondInvalid(c, "Invalid tournament ID format")
 		return
 	}
 
-	err = h.TournamentService.FinishTournament(tournamentID)
+	err = h.TournamentService.FinishTournament(c.Request.Context(), tournamentID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finish tournament"})
+		respondError(c, err)
 		return
 	}
 
@@ -125,39 +126,102 @@ func (h *TournamentHandler) FinishTournament(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
 // @Router /tournaments/ [post]
 func (h *TournamentHandler) FinishAllTournaments(c *gin.Context) {
-	err := h.TournamentService.FinishAllTournaments()
+	err := h.TournamentService.FinishAllTournaments(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finish all tournaments"})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "All tournaments finished successfully"})
 }
 
+// Results of a level attempt reported to UpdateScore.
+const (
+	levelCompleted = "completed"
+	levelFailed    = "failed"
+)
+
 // @Summary Update Score
-// @Description It updates the score of the user.
+// @Description It updates the score of the user for a completed level, which needs at least one life. A failed level costs a life instead and returns the lives left.
 // @Tags Tournaments
 // @Accept json
 // @Produce json
+// @Param result query string false "Result of the level attempt" Enums(completed, failed) default(completed)
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Failure 403 {object} handlers.Problem
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
 // @Router /tournaments/ [put]
 func (h *TournamentHandler) UpdateScore(c *gin.Context) {
 	userIDStr := c.Param("id")
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondInvalid(c, "Invalid user ID format")
+		return
+	}
+
+	switch c.DefaultQuery("result", levelCompleted) {
+	case levelCompleted:
+	case levelFailed:
+		lives, err := h.TournamentService.FailLevel(c.Request.Context(), userID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, lives)
+		return
+	default:
+		respondInvalid(c, "Result must be completed or failed")
 		return
 	}
 
-	err = h.TournamentService.UpdateScore(userID)
+	err = h.TournamentService.UpdateScore(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Score updated successfully"})
 }
+
+// @Summary Enter Team Tournament
+// @Description It enters the team to today's team tournament, the user must be the leader or an officer
+// @Tags Tournaments
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Router /tournaments/team/enter/{id} [post]
+func (h *TournamentHandler) EnterTeamTournament(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid team ID format")
+		return
+	}
+
+	var req struct {
+		UserID uuid.UUID `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalid(c, "Invalid JSON input")
+		return
+	}
+
+	tournament, err := h.TournamentService.EnterTeamTournament(c.Request.Context(), teamID, req.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Team entered the tournament successfully",
+		"tournament_id": tournament.ID,
+		"start_time":    tournament.StartTime,
+		"end_time":      tournament.EndTime,
+		"team_count":    tournament.UserCount,
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CreateUserRequest is the body of POST /users/. The ID, coins and level are assigned by the server.
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=20,username,clean" example:"blaster_42"`
+	Country  string `json:"country" binding:"omitempty,country" example:"TR"`
+
+	ID    json.RawMessage `json:"id,omitempty" binding:"isdefault" swaggerignore:"true"`
+	Coins json.RawMessage `json:"coins,omitempty" binding:"isdefault" swaggerignore:"true"`
+	Level json.RawMessage `json:"level,omitempty" binding:"isdefault" swaggerignore:"true"`
+}
+
+// UpdateUserRequest is the body of PUT /users/{id}. Coins and level only change through gameplay.
+type UpdateUserRequest struct {
+	Country *string `json:"country" binding:"required,country" example:"DE"`
+
+	ID       json.RawMessage `json:"id,omitempty" binding:"isdefault" swaggerignore:"true"`
+	Username json.RawMessage `json:"username,omitempty" binding:"isdefault" swaggerignore:"true"`
+	Coins    json.RawMessage `json:"coins,omitempty" binding:"isdefault" swaggerignore:"true"`
+	Level    json.RawMessage `json:"level,omitempty" binding:"isdefault" swaggerignore:"true"`
+}
+
+// ChangeUsernameRequest is the body of PATCH /users/{id}/username.
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=20,username,clean" example:"blaster_43"`
+}
+
+// BatchUsersRequest is the body of POST /users/batch.
+type BatchUsersRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100,dive,uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+}
+
+// BatchUsersResponse holds the found users in the order they were asked for,
+// and the IDs that have no user.
+type BatchUsersResponse struct {
+	Users   []UserResponse `json:"users"`
+	Missing []uuid.UUID    `json:"missing"`
+}
+
+// UserResponse is the public view of a user.
+type UserResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Coins    int       `json:"coins"`
+	Level    int       `json:"level"`
+	Country  string    `json:"country"`
+}
+
+func newUserResponse(user *models.User) UserResponse {
+	return UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		Coins:    user.Coins,
+		Level:    user.Level,
+		Country:  user.Country,
+	}
+}
+
+func newUserResponses(users []models.User) []UserResponse {
+	responses := make([]UserResponse, len(users))
+	for i := range users {
+		responses[i] = newUserResponse(&users[i])
+	}
+	return responses
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"good-api/internal/models"
 	"good-api/internal/repositories"
 	"good-api/internal/services"
@@ -38,27 +39,30 @@ func NewUserHandlerwithService(userRepo *repositories.UserRepository, userServic
 }
 
 // @Summary Create user
-// @Description Creates a new user with username, country, level, coins and ID
+// @Description Creates a new user with a username and country. The ID, coins and level are assigned by the server.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Success 201 {object} models.User
-// @Failure 400 {object} map[string]string
+// @Param user body CreateUserRequest true "New user"
+// @Success 201 {object} handlers.UserResponse
+// @Failure 400 {object} handlers.Problem
+// @Failure 409 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
 // @Router /users/ [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON input"})
+	var req CreateUserRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	createdUser, err := h.UserService.CreateUser(&user)
+	user := models.User{Username: req.Username, Country: req.Country}
+	createdUser, err := h.UserService.CreateUser(c.Request.Context(), &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, createdUser)
+	c.JSON(http.StatusCreated, newUserResponse(createdUser))
 }
 
 // @Summary Get user
@@ -66,23 +70,24 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Success 201 {object} models.User
-// @Failure 400 {object} map[string]string
-// @Router /users/ [get]
+// @Success 200 {object} handlers.UserResponse
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	userIDstr := c.Param("id")
 	userID, err := uuid.Parse(userIDstr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		respondInvalid(c, "Invalid user ID format")
 		return
 	}
-	user, err := h.UserRepo.GetUserByID(userID)
+	user, err := h.UserRepo.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		respondError(c, services.NotFoundAs(err, services.ErrUserNotFound))
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, newUserResponse(user))
 }
 
 // @Summary Gets all users
@@ -90,89 +95,251 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Success 201 {object} []models.User
-// @Failure 400 {object} map[string]string
+// @Success 200 {object} []handlers.UserResponse
+// @Failure 400 {object} handlers.Problem
 // @Router /users/ [get]
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.UserRepo.GetAllUsers()
+	users, err := h.UserRepo.GetAllUsers(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newUserResponses(users))
+}
+
+// @Summary Get users in bulk
+// @Description Gets up to 100 users in one request, such as the players of a tournament group. Users are returned in the order of the IDs; IDs without a user are listed as missing.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param ids body BatchUsersRequest true "User IDs"
+// @Success 200 {object} handlers.BatchUsersResponse
+// @Failure 400 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
+// @Router /users/batch [post]
+func (h *UserHandler) GetUsersBatch(c *gin.Context) {
+	var req BatchUsersRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Duplicates are answered once
+	ids := make([]uuid.UUID, 0, len(req.IDs))
+	seen := make(map[uuid.UUID]bool, len(req.IDs))
+	for _, raw := range req.IDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			respondInvalid(c, "Invalid user ID format")
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	users, err := h.UserRepo.GetUsersByIDs(c.Request.Context(), ids)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "There are no users"})
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, users)
+
+	resp := BatchUsersResponse{Users: make([]UserResponse, 0, len(users)), Missing: []uuid.UUID{}}
+	for _, id := range ids {
+		user, ok := users[id]
+		if !ok {
+			resp.Missing = append(resp.Missing, id)
+			continue
+		}
+		resp.Users = append(resp.Users, newUserResponse(&user))
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // @Summary Update user
-// @Description Updates the user with new information
+// @Description Updates the user's country. The ID, username, coins and level cannot be changed here.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Success 201 {object} models.User
-// @Failure 400 {object} map[string]string
-// @Router /users/ [put]
+// @Param id path string true "User ID"
+// @Param user body UpdateUserRequest true "Changed fields"
+// @Success 200 {object} handlers.UserResponse
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
+// @Router /users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	if h.UserService == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "UserService is not initialized"})
+		respondError(c, errors.New("UserService is not initialized"))
 		return
 	}
 
 	userIDstr := c.Param("id")
 	userID, err := uuid.Parse(userIDstr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		respondInvalid(c, "Invalid ID format")
 		return
 	}
-	var updateData models.User
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid input"})
+	var req UpdateUserRequest
+	if !bindJSON(c, &req) {
 		return
 	}
-	updateData.ID = userID
 
-	updatedUser, err := h.UserService.UpdateUser(&updateData)
+	updatedUser, err := h.UserService.UpdateUser(c.Request.Context(), userID, services.UserUpdate{Country: req.Country})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "anlamsiz hata"})
+		respondError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, updatedUser)
+	c.JSON(http.StatusOK, newUserResponse(updatedUser))
+}
+
+// @Summary Change username
+// @Description Changes the user's username for a coin cost set by the server. A user can change their username once per cooldown period, and their old username stays held for them for a while.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body ChangeUsernameRequest true "New username"
+// @Success 200 {object} handlers.UserResponse
+// @Failure 400 {object} handlers.Problem
+// @Failure 402 {object} handlers.Problem
+// @Failure 403 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Failure 409 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
+// @Router /users/{id}/username [patch]
+func (h *UserHandler) ChangeUsername(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid ID format")
+		return
+	}
+	var req ChangeUsernameRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.UserService.ChangeUsername(c.Request.Context(), userID, req.Username)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newUserResponse(user))
 }
 
 // @Summary Delete user
-// @Description Deletes the user completely
+// @Description Deletes the user's account. The user leaves their team, friends and every leaderboard at once, and is erased for good after the retention period.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Success 201 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} handlers.Problem
 // @Router /users/ [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idParam := c.Param("id")
 	userID, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		respondInvalid(c, "Invalid user ID")
 		return
 	}
 
-	err = h.UserService.DeleteUser(userID)
+	err = h.UserService.DeleteUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
 
+// @Summary Export user data
+// @Description Returns everything stored about the user as a JSON archive: profile, tournament results, team membership, friendships, contact hash and domain events.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.UserExport
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /users/{id}/export [get]
+func (h *UserHandler) ExportUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+
+	export, err := h.UserService.ExportUser(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="user-`+userID.String()+`.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+// @Summary Get lives
+// @Description Returns the user's lives, counting the lives regenerated so far, and when the next one comes back.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.Lives
+// @Failure 400 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Router /users/{id}/lives [get]
+func (h *UserHandler) GetLives(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+
+	lives, err := h.UserService.GetLives(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lives)
+}
+
+// @Summary Refill lives
+// @Description Gives the user all their lives back for a coin cost set by the server.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Success 200 {object} models.Lives
+// @Failure 400 {object} handlers.Problem
+// @Failure 402 {object} handlers.Problem
+// @Failure 404 {object} handlers.Problem
+// @Failure 409 {object} handlers.Problem
+// @Router /users/{id}/lives/refill [post]
+func (h *UserHandler) RefillLives(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid user ID")
+		return
+	}
+
+	lives, err := h.UserService.RefillLives(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lives)
+}
+
 func (h *UserHandler) IncreaseLevel(c *gin.Context) {
 	idParam := c.Param("id")
 	userID, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		respondInvalid(c, "Invalid user ID")
 		return
 	}
 
-	err = h.UserService.IncreaseLevel(userID)
+	err = h.UserService.IncreaseLevel(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"good-api/internal/countries"
+	"good-api/internal/profanity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// CodeValidationFailed is the problem code of a request body that failed validation.
+const CodeValidationFailed = "validation_failed"
+
+// FieldError describes why a single field of a request body was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Custom validation tags usable in binding tags next to the built-in ones.
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		panic("handlers: unexpected validator engine")
+	}
+
+	// Report fields by their JSON names
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	must(v.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return usernamePattern.MatchString(fl.Field().String())
+	}))
+	must(v.RegisterValidation("clean", func(fl validator.FieldLevel) bool {
+		return !profanity.Contains(fl.Field().String())
+	}))
+	must(v.RegisterValidation("country", func(fl validator.FieldLevel) bool {
+		_, ok := countries.Normalize(fl.Field().String())
+		return ok
+	}))
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// bindJSON binds the request body into req and validates it. If that fails, it writes
+// the problem response, listing every invalid field, and returns false.
+func bindJSON(c *gin.Context, req interface{}) bool {
+	err := c.ShouldBindJSON(req)
+	if err == nil {
+		return true
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		respondInvalid(c, "Invalid JSON input")
+		return false
+	}
+
+	problem := newProblem(c, http.StatusUnprocessableEntity, CodeValidationFailed, "request body has invalid fields")
+	problem.Errors = fieldErrors(validationErrs)
+	writeProblem(c, problem)
+	return false
+}
+
+// ValidateRequest checks req against its binding tags and lists every invalid field.
+// It lets the gRPC API apply the same rules to its requests as the REST API.
+func ValidateRequest(req interface{}) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if err := binding.Validator.ValidateStruct(req); errors.As(err, &validationErrs) {
+		return fieldErrors(validationErrs)
+	}
+	return nil
+}
+
+func fieldErrors(validationErrs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, fieldError(fe))
+	}
+	return fields
+}
+
+// fieldError turns a failed validation tag into a stable code and an English message.
+func fieldError(fe validator.FieldError) FieldError {
+	field := FieldError{Field: fe.Field()}
+	switch fe.Tag() {
+	case "required":
+		field.Code, field.Message = "required", "is required"
+	case "min":
+		if fe.Kind() == reflect.Slice {
+			field.Code, field.Message = "too_few", "must have at least "+fe.Param()+" items"
+		} else {
+			field.Code, field.Message = "too_short", "must be at least "+fe.Param()+" characters"
+		}
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			field.Code, field.Message = "too_many", "must have at most "+fe.Param()+" items"
+		} else {
+			field.Code, field.Message = "too_long", "must be at most "+fe.Param()+" characters"
+		}
+	case "uuid":
+		field.Code, field.Message = "invalid_uuid", "must be a UUID"
+	case "username":
+		field.Code, field.Message = "invalid_characters", "may only contain letters, digits and underscores"
+	case "clean":
+		field.Code, field.Message = "profanity", "contains a word that is not allowed"
+	case "country":
+		field.Code, field.Message = "unknown_country", "must be an ISO 3166-1 alpha-2 code or a country name"
+	case "isdefault":
+		field.Code, field.Message = "read_only", "cannot be set by the client"
+	default:
+		field.Code, field.Message = "invalid", "is invalid"
+	}
+	return field
+}
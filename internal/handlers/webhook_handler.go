@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"good-api/internal/models"
+	"good-api/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	WebhookService *services.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(ws *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{WebhookService: ws}
+}
+
+// CreateWebhookSubscriptionRequest subscribes a partner URL to domain event types.
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+	// Secret signs the deliveries; a random one is generated when it is empty
+	Secret string `json:"secret"`
+}
+
+// WebhookSubscriptionCreated is a new subscription with its secret, which is not shown again.
+type WebhookSubscriptionCreated struct {
+	models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// @Summary Create webhook subscription
+// @Description Subscribes a partner URL to domain event types. Deliveries are signed with HMAC-SHA256 using the secret, which is only returned here. The URL must use https and name a public host.
+// @Tags Admin
+// @Param Authorization header string true "Bearer admin token"
+// @Accept json
+// @Produce json
+// @Param subscription body CreateWebhookSubscriptionRequest true "New subscription"
+// @Success 201 {object} handlers.WebhookSubscriptionCreated
+// @Failure 400 {object} handlers.Problem
+// @Failure 422 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/webhooks/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req CreateWebhookSubscriptionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	subscription, err := h.WebhookService.CreateSubscription(c.Request.Context(), req.URL, req.EventTypes, req.Secret)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, WebhookSubscriptionCreated{WebhookSubscription: *subscription, Secret: subscription.Secret})
+}
+
+// @Summary List webhook subscriptions
+// @Description Lists the webhook subscriptions, without their secrets
+// @Tags Admin
+// @Param Authorization header string true "Bearer admin token"
+// @Produce json
+// @Success 200 {object} []models.WebhookSubscription
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/webhooks/subscriptions [get]
+func (h *WebhookHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.WebhookService.GetSubscriptions(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// @Summary Delete webhook subscription
+// @Description Deletes a webhook subscription and drops its pending deliveries. Its dead letters are kept.
+// @Tags Admin
+// @Param Authorization header string true "Bearer admin token"
+// @Success 204
+// @Failure 404 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/webhooks/subscriptions/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid subscription ID format")
+		return
+	}
+
+	if err := h.WebhookService.DeleteSubscription(c.Request.Context(), subscriptionID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List failed webhook deliveries
+// @Description Lists the webhook deliveries that failed every attempt, newest first
+// @Tags Admin
+// @Param Authorization header string true "Bearer admin token"
+// @Produce json
+// @Param limit query int false "Most dead letters to return" default(100)
+// @Param include_replayed query bool false "Include dead letters that were already replayed"
+// @Success 200 {object} []models.WebhookDeadLetter
+// @Failure 400 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/webhooks/dead-letters [get]
+func (h *WebhookHandler) GetDeadLetters(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 {
+		respondInvalid(c, "Invalid limit value")
+		return
+	}
+	includeReplayed := c.Query("include_replayed") == "true"
+
+	deadLetters, err := h.WebhookService.GetDeadLetters(c.Request.Context(), limit, includeReplayed)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+// @Summary Replay failed webhook delivery
+// @Description Queues a dead-lettered delivery again with a fresh set of attempts
+// @Tags Admin
+// @Param Authorization header string true "Bearer admin token"
+// @Produce json
+// @Success 202 {object} models.WebhookDeadLetter
+// @Failure 404 {object} handlers.Problem
+// @Failure 409 {object} handlers.Problem
+// @Failure 401 {object} handlers.Problem
+// @Router /admin/webhooks/dead-letters/{id}/replay [post]
+func (h *WebhookHandler) ReplayDeadLetter(c *gin.Context) {
+	deadLetterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondInvalid(c, "Invalid dead letter ID format")
+		return
+	}
+
+	deadLetter, err := h.WebhookService.ReplayDeadLetter(c.Request.Context(), deadLetterID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, deadLetter)
+}
@@ -0,0 +1,184 @@
+package metrics
+
+/*
+The metrics package holds every Prometheus metric exported on /metrics.
+Metrics are registered once on the default registry, so packages record them
+directly instead of passing collectors through the constructors.
+*/
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "goodapi"
+
+// Reasons a tournament entry is rejected, used as the "reason" label.
+const (
+	RejectEntryClosed        = "entry_closed"
+	RejectNotFound           = "not_found"
+	RejectRequirementsNotMet = "requirements_not_met"
+	RejectAlreadyEntered     = "already_entered"
+	RejectNotAllowed         = "not_allowed"
+	RejectInternal           = "internal"
+)
+
+// HTTP
+var HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "http_request_duration_seconds",
+	Help:      "Duration of HTTP requests by route, method and status code.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+// GRPCRequestDuration is the gRPC counterpart of HTTPRequestDuration.
+var GRPCRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "grpc_request_duration_seconds",
+	Help:      "Duration of gRPC calls by method and status code. Streams are measured until they end.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "code"})
+
+// RateLimited counts requests rejected with 429 by route group.
+var RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "rate_limited_total",
+	Help:      "Requests rejected by the rate limiter by route group.",
+}, []string{"group"})
+
+// Gameplay
+var (
+	TournamentEntries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tournament_entries_total",
+		Help:      "Accepted tournament entries by tournament mode.",
+	}, []string{"mode"})
+
+	TournamentEntriesRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tournament_entries_rejected_total",
+		Help:      "Rejected tournament entries by tournament mode and reason.",
+	}, []string{"mode", "reason"})
+
+	CoinsPaidOut = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coins_paid_out_total",
+		Help:      "Coins paid out as tournament rewards by tournament mode.",
+	}, []string{"mode"})
+
+	LevelUps = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "level_ups_total",
+		Help:      "Level-ups by source: a completed level or a tournament reward.",
+	}, []string{"source"})
+
+	TournamentFinalizationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tournament_finalization_duration_seconds",
+		Help:      "Time taken to finish a tournament and pay out its rewards.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"mode"})
+
+	ActiveTournaments = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_tournaments",
+		Help:      "Number of active tournaments by tournament mode.",
+	}, []string{"mode"})
+
+	TournamentFillRatio = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tournament_fill_ratio",
+		Help:      "Occupied share of the seats of all active tournaments, between 0 and 1.",
+	}, []string{"mode"})
+)
+
+// Infrastructure
+var (
+	OutboxEventsDispatched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_events_dispatched_total",
+		Help:      "Domain events delivered to every outbox sink by event type.",
+	}, []string{"type"})
+
+	OutboxDeliveryFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_delivery_failures_total",
+		Help:      "Failed deliveries of domain events by sink.",
+	}, []string{"sink"})
+
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by result: delivered, retry or dead_letter.",
+	}, []string{"result"})
+
+	LivesLost = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "lives_lost_total",
+		Help:      "Lives lost to failed levels.",
+	})
+
+	LivesRefills = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "lives_refills_total",
+		Help:      "Lives refilled with coins.",
+	})
+
+	ShopPurchases = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "shop_items_purchased_total",
+		Help:      "Shop items bought with coins, by item.",
+	}, []string{"item"})
+
+	ShopCoinsSpent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "shop_coins_spent_total",
+		Help:      "Coins spent in the shop.",
+	})
+
+	DailyRewardsClaimed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "daily_rewards_claimed_total",
+		Help:      "Daily login rewards claimed.",
+	})
+
+	UsersErased = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "users_erased_total",
+		Help:      "Deleted users erased for good after the retention period.",
+	})
+
+	ProfileCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "profile_cache_lookups_total",
+		Help:      "User profile cache lookups by result: hit, miss or error.",
+	}, []string{"result"})
+
+	ProfileCacheSharedLoads = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "profile_cache_shared_loads_total",
+		Help:      "Profile cache misses answered by a database query already in flight for the same user.",
+	})
+
+	RedisCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Duration of Redis commands and pipelines by command and result.",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1},
+	}, []string{"command", "result"})
+
+	PostgresQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "postgres_query_duration_seconds",
+		Help:      "Duration of Postgres statements by operation and result.",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5},
+	}, []string{"operation", "result"})
+)
+
+// Result returns the "result" label value of an operation.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
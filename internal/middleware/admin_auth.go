@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"good-api/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth lets a request through only if it carries the admin token as a bearer token
+// in the Authorization header. Without a configured token every request is rejected,
+// so the admin API stays closed until an operator sets one.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			handlers.RespondProblem(c, http.StatusUnauthorized, "unauthorized", "a valid admin token is required")
+			return
+		}
+		c.Next()
+	}
+}
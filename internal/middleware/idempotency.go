@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"good-api/internal/cache"
+	"good-api/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen key of a state-changing request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// A reserved key is freed after this long if its request never completes, for example on a crash
+	idempotencyPendingTTL = time.Minute
+)
+
+// Idempotency replays the stored response when a request is retried with the same
+// Idempotency-Key header, so the change is applied only once. Keys are scoped to the
+// :id path parameter (the user or team acting), or to the user_id of the body on routes
+// without one, and stored for ttl. Reusing a key with a
+// different payload is rejected, as is a retry while the first request is still running.
+// Requests without the header are not affected.
+func Idempotency(ttl time.Duration, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			handlers.RespondProblem(c, http.StatusBadRequest, "idempotency_key_invalid", "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			handlers.RespondProblem(c, http.StatusBadRequest, handlers.CodeInvalidRequest, "could not read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scope := c.Param("id")
+		if scope == "" {
+			// Routes without :id, like /shop/purchase, name the user in the body
+			scope = ByUserBody("user_id")(c)
+		}
+		fingerprint := requestFingerprint(c, body)
+
+		reserved, stored, err := cache.ReserveIdempotencyKey(ctx, scope, key, fingerprint, idempotencyPendingTTL)
+		if err != nil {
+			// Without Redis a retry could be applied twice, so the request is refused instead
+			logger.ErrorContext(ctx, "failed to reserve idempotency key", "error", err)
+			handlers.RespondProblem(c, http.StatusServiceUnavailable, handlers.CodeUnavailable, "service unavailable, try again later")
+			return
+		}
+
+		if !reserved {
+			switch {
+			case stored == nil || stored.Pending && stored.Fingerprint == fingerprint:
+				c.Header("Retry-After", "1")
+				handlers.RespondProblem(c, http.StatusConflict, "idempotency_request_in_progress", "a request with this Idempotency-Key is still being processed")
+			case stored.Fingerprint != fingerprint:
+				handlers.RespondProblem(c, http.StatusUnprocessableEntity, "idempotency_key_reused", "Idempotency-Key was already used with a different request")
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(stored.Status, stored.ContentType, stored.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The request context may have timed out, but the outcome must still be recorded
+		saveCtx := context.WithoutCancel(ctx)
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			// Failed requests may be retried with the same key
+			if err := cache.ReleaseIdempotencyKey(saveCtx, scope, key); err != nil {
+				logger.ErrorContext(ctx, "failed to release idempotency key", "error", err)
+			}
+			return
+		}
+
+		response := cache.IdempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := cache.SaveIdempotentResponse(saveCtx, scope, key, response, ttl); err != nil {
+			logger.ErrorContext(ctx, "failed to save idempotent response", "error", err)
+		}
+	}
+}
+
+// requestFingerprint identifies a request by its method, route and body.
+func requestFingerprint(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while writing it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
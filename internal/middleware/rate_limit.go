@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/handlers"
+	"good-api/internal/metrics"
+	"good-api/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Identity names who a request is counted against for rate limiting.
+type Identity func(c *gin.Context) string
+
+// ByUserParam counts requests against the user in the given path parameter,
+// or against the client IP when the route has no such parameter.
+func ByUserParam(param string) Identity {
+	return func(c *gin.Context) string {
+		if id := c.Param(param); id != "" {
+			return "user:" + id
+		}
+		return ByClientIP(c)
+	}
+}
+
+// ByUserQuery counts requests against the user in the given query parameter,
+// or against the client IP when it is missing.
+func ByUserQuery(param string) Identity {
+	return func(c *gin.Context) string {
+		if id := c.Query(param); id != "" {
+			return "user:" + id
+		}
+		return ByClientIP(c)
+	}
+}
+
+// ByUserBody counts requests against the user in the given field of the JSON body,
+// or against the client IP when the body does not name one. The body is left for the handler.
+func ByUserBody(field string) Identity {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ByClientIP(c)
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return ByClientIP(c)
+		}
+
+		var fields map[string]json.RawMessage
+		var id string
+		if json.Unmarshal(body, &fields) != nil || json.Unmarshal(fields[field], &id) != nil || id == "" {
+			return ByClientIP(c)
+		}
+		return "user:" + id
+	}
+}
+
+// ByClientIP counts requests against the client IP.
+func ByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimit limits the requests of each identity to a route group and answers 429 with
+// Retry-After once the limit is reached. Every response carries the X-RateLimit-* headers.
+// Requests counted against a user also take a token from the perIP bucket of their client IP,
+// so naming other users, which clients are free to do, cannot get around the limit.
+// A perIP limit without requests turns that off.
+func RateLimit(limiter ratelimit.Limiter, group string, limit config.RateLimit, identify Identity, perIP config.RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := identify(c)
+		if perIP.Requests > 0 && !strings.HasPrefix(key, "ip:") {
+			if !take(c, limiter, group, group+":"+ByClientIP(c), perIP) {
+				return
+			}
+		}
+		if !take(c, limiter, group, group+":"+key, limit) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// take takes a token from the bucket of key and sets the X-RateLimit-* headers. When the
+// bucket is empty, it answers 429 and returns false.
+func take(c *gin.Context, limiter ratelimit.Limiter, group, key string, limit config.RateLimit) bool {
+	result := limiter.Take(c.Request.Context(), key, limit)
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+	if !result.Allowed {
+		metrics.RateLimited.WithLabelValues(group).Inc()
+		c.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+		handlers.RespondProblem(c, http.StatusTooManyRequests, "rate_limited", "too many requests, try again later")
+		return false
+	}
+	return true
+}
+
+// Limits builds the rate limit middleware of a route group, counting requests by identify.
+type Limits func(group string, identify Identity) gin.HandlerFunc
+
+// NewLimits returns the rate limits of the configured route groups. Groups without
+// a configured limit, or every group when rate limiting is disabled, are not limited.
+func NewLimits(limiter ratelimit.Limiter, cfg config.RateLimitConfig) Limits {
+	return func(group string, identify Identity) gin.HandlerFunc {
+		limit, ok := cfg.Group(group)
+		if !cfg.Enabled || !ok {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return RateLimit(limiter, group, limit, identify, limit.Scaled(cfg.IPFactor))
+	}
+}
+
+// seconds rounds d up to whole seconds, as the headers expect.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
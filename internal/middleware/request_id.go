@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"good-api/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header used to pass a request ID in and out of the API.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestLogger assigns every request an ID, stores it in the request context so it
+// reaches services, repositories and Redis calls, and logs the request when it completes.
+// A request ID sent by the client is reused so calls can be traced across services.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+
+		ctx := logging.WithRequestID(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(RequestIDHeader, requestID)
+
+		start := time.Now()
+		c.Next()
+
+		level := slog.LevelInfo
+		if c.Writer.Status() >= 500 {
+			level = slog.LevelError
+		}
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.FullPath(),
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		// Errors behind a response, such as the cause of a 500, are only logged
+		if err := c.Errors.Last(); err != nil {
+			attrs = append(attrs, "error", err.Err)
+		}
+		logger.Log(ctx, level, "request completed", attrs...)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DailyRewardClaim is a user's daily login reward for one UTC day. A user can claim once per day.
+type DailyRewardClaim struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_daily_reward_user_day,priority:1" json:"user_id"`
+	Day       time.Time `gorm:"type:date;not null;uniqueIndex:idx_daily_reward_user_day,priority:2" json:"day"`
+	Streak    int       `gorm:"not null" json:"streak"` // Consecutive days claimed, this one included
+	Coins     int       `gorm:"not null" json:"coins"`
+	ClaimedAt time.Time `gorm:"not null" json:"claimed_at"`
+}
+
+// DailyReward is the result of claiming the daily login reward.
+type DailyReward struct {
+	Claim DailyRewardClaim `json:"claim"`
+	// Set when the reward of the day was already claimed; nothing was paid again
+	AlreadyClaimed bool `json:"already_claimed"`
+	Balance        int  `json:"balance"` // The user's coins after the claim
+}
+
+// DailyRewardStatus is a user's login streak and what the next claim pays.
+type DailyRewardStatus struct {
+	Streak       int       `json:"streak"`
+	ClaimedToday bool      `json:"claimed_today"`
+	NextReward   int       `json:"next_reward"`
+	NextClaimAt  time.Time `json:"next_claim_at"` // Start of the UTC day the next reward can be claimed
+	Calendar     []int     `json:"calendar"`
+}
+
+// UTCDay returns the start of the UTC day of t. Tournaments and daily rewards both run on UTC days.
+func UTCDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+Friendship is stored as directed rows.
+A pending request is a single row from the requester to the receiver.
+An accepted friendship is stored in both directions so a user's friends
+can be read with a single indexed lookup on user_id.
+A block is a single row from the blocker to the blocked user.
+*/
+
+// Friendship statuses
+const (
+	FriendshipPending  = "pending"
+	FriendshipAccepted = "accepted"
+	FriendshipBlocked  = "blocked"
+)
+
+type Friendship struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_friendship_pair;index:idx_friendship_user_status,priority:1" json:"user_id"`
+	FriendID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_friendship_pair;index" json:"friend_id"`
+	Status    string    `gorm:"not null;index:idx_friendship_user_status,priority:2" json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserContactHash links a user to the hash of their device contact (phone number or e-mail).
+// Only the hash is stored, the client hashes the contact before sending it.
+type UserContactHash struct {
+	UserID uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Hash   string    `gorm:"not null;uniqueIndex" json:"hash"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "github.com/google/uuid"
+
+// LeaderboardRow is a ranked user on a leaderboard.
+type LeaderboardRow struct {
+	Rank     int       `json:"rank"`
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Level    int       `json:"level"`
+	Country  string    `json:"country"`
+}
+
+// LeaderboardPage is the top of a leaderboard together with the caller's own row.
+// Me is nil when no user was given or the user is not ranked.
+type LeaderboardPage struct {
+	Leaderboard []LeaderboardRow `json:"leaderboard"`
+	Me          *LeaderboardRow  `json:"me,omitempty"`
+}
+
+// RankInfo is a user's position on a leaderboard together with the users ranked around them.
+// Percentile is the share of ranked users placed below the user.
+type RankInfo struct {
+	Rank       int              `json:"rank"`
+	Total      int              `json:"total"`
+	Percentile float64          `json:"percentile"`
+	Me         LeaderboardRow   `json:"me"`
+	Above      []LeaderboardRow `json:"above"`
+	Below      []LeaderboardRow `json:"below"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Lives is a user's lives at a point in time, with the regenerated lives counted in.
+type Lives struct {
+	Lives    int `json:"lives"`
+	MaxLives int `json:"max_lives"`
+	Coins    int `json:"coins"`
+	// When the next life and the last missing life come back; nil when the lives are full
+	NextLifeAt *time.Time `json:"next_life_at"`
+	FullAt     *time.Time `json:"full_at"`
+}
+
+// RegenerateLives returns the lives a user has at now if they had lives at since and get one
+// back every regen, up to maxLives. It also returns the time regeneration continues from:
+// since moved forward by the regenerated lives, or now once the lives are full.
+func RegenerateLives(lives int, since, now time.Time, maxLives int, regen time.Duration) (int, time.Time) {
+	if lives >= maxLives {
+		return lives, now
+	}
+
+	regenerated := int(now.Sub(since) / regen)
+	if regenerated <= 0 {
+		return lives, since
+	}
+	if lives+regenerated >= maxLives {
+		return maxLives, now
+	}
+	return lives + regenerated, since.Add(time.Duration(regenerated) * regen)
+}
+
+// NewLives returns the user's lives at now.
+func NewLives(user *User, now time.Time, maxLives int, regen time.Duration) *Lives {
+	lives, since := RegenerateLives(user.Lives, user.LivesUpdatedAt, now, maxLives, regen)
+
+	view := &Lives{Lives: lives, MaxLives: maxLives, Coins: user.Coins}
+	if lives < maxLives {
+		next := since.Add(regen)
+		full := since.Add(time.Duration(maxLives-lives) * regen)
+		view.NextLifeAt, view.FullAt = &next, &full
+	}
+	return view
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// SchemaMigration records a data migration that has been applied to the database.
+type SchemaMigration struct {
+	Version   string    `gorm:"primaryKey" json:"version"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
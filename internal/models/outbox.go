@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+OutboxEvent is a domain event written in the same transaction as the change it describes,
+so an event is recorded if and only if the change is committed. The outbox dispatcher
+delivers pending events to the configured sinks and marks them dispatched.
+Delivery is at least once: consumers should ignore events whose ID they have seen.
+*/
+
+// Domain event types.
+const (
+	EventTournamentEntered  = "tournament.entered"
+	EventLevelCompleted     = "user.level_completed"
+	EventTournamentFinished = "tournament.finished"
+	EventRewardPaid         = "tournament.reward_paid"
+	EventLevelIncreased     = "tournament.level_increased"
+)
+
+type OutboxEvent struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Type        string    `gorm:"not null;index" json:"type"`
+	AggregateID uuid.UUID `gorm:"type:uuid;not null" json:"aggregate_id"` // The tournament or user the event is about
+	Payload     string    `gorm:"type:jsonb;not null" json:"payload"`
+	CreatedAt   time.Time `gorm:"not null;index" json:"created_at"`
+	// Pending events are claimed once this time has passed, it moves forward on claims and retries
+	AvailableAt  time.Time  `gorm:"not null;index" json:"available_at"`
+	DispatchedAt *time.Time `gorm:"index" json:"dispatched_at,omitempty"`
+	Attempts     int        `gorm:"not null;default:0" json:"attempts"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// TournamentEnteredEvent is the payload of EventTournamentEntered.
+type TournamentEnteredEvent struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Level        int       `json:"level"`
+}
+
+// LevelCompletedEvent is the payload of EventLevelCompleted.
+// TournamentID is set when the level counted as a tournament score.
+type LevelCompletedEvent struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	TournamentID *uuid.UUID `json:"tournament_id,omitempty"`
+	Level        int        `json:"level"`
+	Coins        int        `json:"coins,omitempty"`
+}
+
+// TournamentFinishedEvent is the payload of EventTournamentFinished.
+type TournamentFinishedEvent struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	Mode         string    `json:"mode"`
+	Participants int       `json:"participants"`
+}
+
+// RewardPaidEvent is the payload of EventRewardPaid. TeamID is set for team tournament shares.
+type RewardPaidEvent struct {
+	TournamentID uuid.UUID  `json:"tournament_id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	TeamID       *uuid.UUID `json:"team_id,omitempty"`
+	Rank         int        `json:"rank"`
+	Coins        int        `json:"coins"`
+}
+
+// LevelIncreasedEvent is the payload of EventLevelIncreased, written when a reward
+// raises the user's tournament level without a completed level.
+type LevelIncreasedEvent struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Level        int       `json:"level"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Boosters sold in the shop.
+const (
+	BoosterHammer     = "hammer"      // Clears a single tile
+	BoosterShuffle    = "shuffle"     // Reshuffles the board
+	BoosterExtraMoves = "extra_moves" // Adds five moves to a level
+)
+
+// ShopItem is an item of the shop catalog. Prices are managed by admins.
+type ShopItem struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `gorm:"not null;default:''" json:"description"`
+	Price       int       `gorm:"not null" json:"price"` // In coins
+	Available   bool      `gorm:"not null;default:true" json:"available"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// InventoryItem is how many of a shop item a user owns.
+type InventoryItem struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	ItemID    string    `gorm:"primaryKey" json:"item_id"`
+	Quantity  int       `gorm:"not null" json:"quantity"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Purchase is the result of buying a shop item.
+type Purchase struct {
+	ItemID   string `json:"item_id"`
+	Bought   int    `json:"bought"`
+	Paid     int    `json:"paid"`
+	Quantity int    `json:"quantity"` // Owned after the purchase
+	Coins    int    `json:"coins"`    // Left after the purchase
+}
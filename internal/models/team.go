@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+Team represents a clan that players can join together.
+A user can be a member of at most one team at a time.
+Teams compete against each other in team tournaments.
+*/
+
+// Team member roles, ordered from most to least privileged.
+const (
+	TeamRoleLeader  = "leader"
+	TeamRoleOfficer = "officer"
+	TeamRoleMember  = "member"
+)
+
+type Team struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name        string    `gorm:"uniqueIndex;not null" json:"name"`
+	Description string    `json:"description"`
+	OwnerID     uuid.UUID `gorm:"type:uuid;not null" json:"owner_id"`
+	MemberCount int       `gorm:"not null;default:0" json:"member_count"`
+	MaxMembers  int       `gorm:"not null;default:50" json:"max_members"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TeamMember struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	TeamID   uuid.UUID `gorm:"type:uuid;not null;index" json:"team_id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"` // One team per user
+	Role     string    `gorm:"not null;default:'member'" json:"role"`
+	JoinedAt time.Time `gorm:"not null" json:"joined_at"`
+}
+
+// TeamTournamentEntry records a team taking part in a team tournament.
+// Score is the sum of the level-ups of its members, persisted when the tournament finishes.
+type TeamTournamentEntry struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	TournamentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_team_tournament_entry,priority:1" json:"tournament_id"`
+	TeamID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_team_tournament_entry,priority:2;index" json:"team_id"` // A team enters a tournament once
+	Score        int       `gorm:"not null;default:0" json:"score"`
+}
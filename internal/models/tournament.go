@@ -10,8 +10,15 @@ import (
 Tournament represents a daily tournament
 Each tournament lasts from 00:00 UTC to 23:59 UTC
 Users are grouped into 35-player groups
+Team tournaments group teams instead of users, UserCount then counts teams
 */
 
+// Tournament modes
+const (
+	TournamentModeSolo = "solo"
+	TournamentModeTeam = "team"
+)
+
 // Represents a tournament event that runs daily.
 type Tournament struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
@@ -20,6 +27,7 @@ type Tournament struct {
 	IsActive  bool      `gorm:"default:true" json:"is_active"`
 	UserCount int       `gorm:"default:0" json:"user_count"`
 	MaxUsers  int       `gorm:"default:35" json:"max_users"`
+	Mode      string    `gorm:"not null;default:'solo'" json:"mode"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
@@ -33,3 +41,24 @@ type TournamentParticipant struct {
 	UserID       uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
 	Level        int       `gorm:"not null;default:0" json:"level"`
 }
+
+// TournamentReward records a reward paid to a user for a finished tournament. It is written
+// in the same transaction as the coins, and a user is paid at most once per tournament.
+type TournamentReward struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	TournamentID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_tournament_reward_user,priority:1" json:"tournament_id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_tournament_reward_user,priority:2" json:"user_id"`
+	TeamID       *uuid.UUID `gorm:"type:uuid" json:"team_id,omitempty"`
+	Rank         int        `gorm:"not null" json:"rank"`
+	Coins        int        `gorm:"not null" json:"coins"`
+	PaidAt       time.Time  `gorm:"not null" json:"paid_at"`
+}
+
+// TournamentStats summarizes the active tournaments of one mode.
+// Participants counts teams in team tournaments, like UserCount.
+type TournamentStats struct {
+	Mode         string
+	Active       int
+	Participants int
+	Seats        int
+}
@@ -1,11 +1,40 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type User struct {
-	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
-	Username string    `json:"username" gorm:"uniques;not null"`
-	Coins    int       `json:"coins" gorm:"default:1000"`
-	Level    int       `json:"level" gorm:"default:1"`
-	Country  string    `json:"country" gorm:"not null;default:'Unkown'"`
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	// Unique among users that are not deleted, ignoring case (idx_users_username_lower)
+	Username string `json:"username" gorm:"not null"`
+	Coins    int    `json:"coins" gorm:"default:1000"`
+	Level    int    `json:"level" gorm:"default:1"`
+	Country  string `json:"country" gorm:"not null;default:'ZZ'"` // ISO 3166-1 alpha-2 code, ZZ when unknown
+	// Lives the user had at LivesUpdatedAt. Lives regenerated since then are added when they
+	// are read (see RegenerateLives), so the stored count is only written when it changes.
+	Lives          int       `json:"lives" gorm:"not null;default:5"`
+	LivesUpdatedAt time.Time `json:"lives_updated_at" gorm:"not null;default:now()"`
+	// Set when the user deletes their account; queries skip the user from then on,
+	// and the row is erased once the retention period has passed
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// UserExport is everything stored about a user, as returned by GET /users/{id}/export.
+type UserExport struct {
+	ExportedAt        time.Time               `json:"exported_at"`
+	User              User                    `json:"user"`
+	Participations    []TournamentParticipant `json:"tournament_participations"`
+	Tournaments       []Tournament            `json:"tournaments"`
+	Rewards           []TournamentReward      `json:"tournament_rewards"`
+	TeamMembership    *TeamMember             `json:"team_membership"`
+	Friendships       []Friendship            `json:"friendships"`
+	ContactHash       *UserContactHash        `json:"contact_hash"`
+	Events            []OutboxEvent           `json:"events"`
+	UsernameHistory   []UsernameChange        `json:"username_history"`
+	Inventory         []InventoryItem         `json:"inventory"`
+	DailyRewardClaims []DailyRewardClaim      `json:"daily_reward_claims"`
 }
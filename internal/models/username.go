@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsernameChange records a change of a user's username. The old username stays held for
+// the user until HeldUntil, so nobody else can take it over right after the change.
+type UsernameChange struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index:idx_username_change_user,priority:1" json:"user_id"`
+	OldUsername string    `gorm:"not null" json:"old_username"`
+	NewUsername string    `gorm:"not null" json:"new_username"`
+	Cost        int       `gorm:"not null" json:"cost"` // Coins paid for the change
+	ChangedAt   time.Time `gorm:"not null;index:idx_username_change_user,priority:2" json:"changed_at"`
+	HeldUntil   time.Time `gorm:"not null" json:"held_until"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+Partners subscribe webhooks to domain event types. Every outbox event is fanned out into
+one delivery per matching subscription, and each delivery is retried with backoff on its own,
+so a slow or failing partner does not hold up the others.
+Deliveries that used up their attempts are moved to the dead-letter table, from which
+an operator can replay them.
+*/
+
+// EventTypes lists the domain event types webhooks can subscribe to.
+var EventTypes = []string{EventTournamentEntered, EventLevelCompleted, EventTournamentFinished, EventRewardPaid, EventLevelIncreased}
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+type WebhookSubscription struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	URL        string    `gorm:"not null" json:"url"`
+	EventTypes []string  `gorm:"type:jsonb;serializer:json;not null" json:"event_types"`
+	// Secret signs the payloads, it is only shown when the subscription is created
+	Secret    string    `gorm:"not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Subscribes reports whether the subscription wants events of eventType.
+func (s WebhookSubscription) Subscribes(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event on its way to one subscription. Payload is the JSON body that is sent.
+type WebhookDelivery struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	SubscriptionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_delivery_event" json:"subscription_id"`
+	EventID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_delivery_event" json:"event_id"` // An event is delivered once per subscription
+	EventType      string    `gorm:"not null" json:"event_type"`
+	Payload        string    `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string    `gorm:"not null;default:'pending';index" json:"status"`
+	Attempts       int       `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time `gorm:"not null;index" json:"next_attempt_at"`
+	LastStatusCode int       `json:"last_status_code,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// WebhookDeadLetter records a delivery that failed every attempt.
+type WebhookDeadLetter struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	DeliveryID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"delivery_id"`
+	SubscriptionID uuid.UUID  `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	EventID        uuid.UUID  `gorm:"type:uuid;not null" json:"event_id"`
+	EventType      string     `gorm:"not null" json:"event_type"`
+	Payload        string     `gorm:"type:jsonb;not null" json:"payload"`
+	Attempts       int        `gorm:"not null" json:"attempts"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error"`
+	FailedAt       time.Time  `gorm:"not null;index" json:"failed_at"`
+	ReplayedAt     *time.Time `json:"replayed_at,omitempty"`
+}
@@ -0,0 +1,144 @@
+package outbox
+
+/*
+Domain events are written to the outbox table in the same transaction as the change they
+describe (see repositories.recordEvent). The dispatcher claims pending events in batches,
+delivers each one to every sink and marks it dispatched. Failed events are retried with
+exponential backoff until they used up their attempts.
+
+Delivery is at least once: an event is delivered again if one of several sinks failed or
+the dispatcher stopped before marking it, so consumers should ignore event IDs they have seen.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/metrics"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Claimed events are hidden from other dispatchers this long, so an event whose
+// dispatcher died while delivering it is picked up again afterwards.
+const claimLease = 5 * time.Minute
+
+// Envelope is the form in which sinks receive an event.
+type Envelope struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope wraps a stored event for delivery.
+func NewEnvelope(event models.OutboxEvent) Envelope {
+	return Envelope{
+		ID:          event.ID,
+		Type:        event.Type,
+		AggregateID: event.AggregateID,
+		OccurredAt:  event.CreatedAt.UTC(),
+		Payload:     json.RawMessage(event.Payload),
+	}
+}
+
+// Sink delivers events to another system.
+type Sink interface {
+	// Name identifies the sink in logs and metrics
+	Name() string
+	Deliver(ctx context.Context, envelope Envelope) error
+}
+
+// Dispatcher delivers the events of the outbox to its sinks.
+type Dispatcher struct {
+	repo        *repositories.OutboxRepository
+	sinks       []Sink
+	batchSize   int
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	logger      *slog.Logger
+}
+
+// NewDispatcher creates a dispatcher that delivers events to sinks.
+func NewDispatcher(repo *repositories.OutboxRepository, sinks []Sink, cfg config.OutboxConfig, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:        repo,
+		sinks:       sinks,
+		batchSize:   cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+		baseBackoff: cfg.BaseBackoff,
+		maxBackoff:  cfg.MaxBackoff,
+		logger:      logger,
+	}
+}
+
+// BatchSize is the most events DispatchPending handles at once.
+func (d *Dispatcher) BatchSize() int {
+	return d.batchSize
+}
+
+// DispatchPending claims a batch of pending events and delivers them, returning how many were claimed.
+// A failed event is scheduled for a retry and does not stop the rest of the batch.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	events, err := d.repo.ClaimPending(ctx, d.batchSize, d.maxAttempts, claimLease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			// The rest of the batch is claimed again when the lease ends
+			return len(events), err
+		}
+		d.dispatch(ctx, event)
+	}
+	return len(events), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event models.OutboxEvent) {
+	envelope := NewEnvelope(event)
+
+	var errs []error
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(ctx, envelope); err != nil {
+			metrics.OutboxDeliveryFailures.WithLabelValues(sink.Name()).Inc()
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+
+	// Marking must happen even if ctx was cancelled during the delivery
+	markCtx := context.WithoutCancel(ctx)
+	if err := errors.Join(errs...); err != nil {
+		retryAt := time.Now().UTC().Add(d.Backoff(event.Attempts))
+		d.logger.WarnContext(ctx, "outbox event delivery failed", "event_id", event.ID, "type", event.Type, "attempt", event.Attempts, "retry_at", retryAt, "error", err)
+		if err := d.repo.MarkFailed(markCtx, event.ID, err, retryAt); err != nil {
+			d.logger.ErrorContext(ctx, "failed to record outbox delivery failure", "event_id", event.ID, "error", err)
+		}
+		return
+	}
+
+	if err := d.repo.MarkDispatched(markCtx, event.ID); err != nil {
+		d.logger.ErrorContext(ctx, "failed to mark outbox event dispatched", "event_id", event.ID, "error", err)
+		return
+	}
+	metrics.OutboxEventsDispatched.WithLabelValues(event.Type).Inc()
+}
+
+// Backoff returns how long to wait before the next attempt after the given number of attempts.
+// Retries of a failed event wait twice as long each time, up to the maximum backoff.
+func (d *Dispatcher) Backoff(attempts int) time.Duration {
+	wait := d.baseBackoff
+	for i := 1; i < attempts && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.maxBackoff)
+}
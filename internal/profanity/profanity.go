@@ -0,0 +1,93 @@
+package profanity
+
+/*
+Usernames and team names are shown on public leaderboards, so names containing
+offensive words are rejected. Matching is done on a folded form of the name so that
+"B4d_W0rd" and "badword" are caught alike.
+*/
+
+import (
+	"strings"
+	"unicode"
+)
+
+// words are matched anywhere in the folded text. Words that commonly appear inside
+// harmless ones (for example "ass" in "class") are left out on purpose.
+var words = []string{
+	"asshole",
+	"bastard",
+	"bitch",
+	"cunt",
+	"fag",
+	"fuck",
+	"hitler",
+	"nazi",
+	"nigg",
+	"penis",
+	"porn",
+	"pussy",
+	"retard",
+	"shit",
+	"slut",
+	"vagina",
+	"whore",
+
+	// Turkish
+	"amcik",
+	"gavat",
+	"orospu",
+	"siktir",
+	"yarrak",
+}
+
+// leet maps digits and symbols commonly used in place of letters.
+var leet = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+}
+
+// Contains reports whether text contains an offensive word.
+func Contains(text string) bool {
+	folded := fold(text)
+	for _, word := range words {
+		if strings.Contains(folded, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// fold lowercases text, undoes leetspeak and Turkish letters and drops everything that is not a letter.
+func fold(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if mapped, ok := leet[r]; ok {
+			r = mapped
+		}
+		switch r {
+		case 'ı':
+			r = 'i'
+		case 'ş':
+			r = 's'
+		case 'ç':
+			r = 'c'
+		case 'ğ':
+			r = 'g'
+		case 'ö':
+			r = 'o'
+		case 'ü':
+			r = 'u'
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package ratelimit
+
+/*
+Requests are limited with token buckets: each identity (a user or a client IP) has a bucket
+per route group that refills at a steady rate up to a burst size, and every request takes
+one token. Buckets live in Redis so the limits hold across instances. When Redis cannot be
+reached, or for single-node development, buckets are kept in memory instead.
+*/
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"good-api/internal/cache"
+	"good-api/internal/config"
+)
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next token, when the request was not allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Limiter takes a token from the bucket of key.
+type Limiter interface {
+	Take(ctx context.Context, key string, limit config.RateLimit) Result
+}
+
+// New returns the limiter of the configured backend.
+func New(cfg config.RateLimitConfig, logger *slog.Logger) Limiter {
+	if cfg.Backend == "memory" {
+		return NewMemoryLimiter()
+	}
+	return &RedisLimiter{fallback: NewMemoryLimiter(), logger: logger}
+}
+
+// interval returns how long it takes to gain one token.
+func interval(limit config.RateLimit) time.Duration {
+	return limit.Per / time.Duration(limit.Requests)
+}
+
+func newResult(allowed bool, tokens float64, limit config.RateLimit) Result {
+	every := interval(limit)
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(limit.Burst) - tokens) * float64(every)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(every))
+	}
+	return result
+}
+
+// RedisLimiter keeps buckets in Redis, shared by every instance.
+type RedisLimiter struct {
+	fallback *MemoryLimiter
+	logger   *slog.Logger
+}
+
+// Take takes a token from the Redis bucket, or from the in-memory one if Redis fails,
+// so an outage neither blocks every request nor turns limiting off.
+func (l *RedisLimiter) Take(ctx context.Context, key string, limit config.RateLimit) Result {
+	allowed, tokens, err := cache.TakeToken(ctx, key, interval(limit), limit.Burst)
+	if err != nil {
+		l.logger.WarnContext(ctx, "rate limit falling back to memory", "error", err)
+		return l.fallback.Take(ctx, key, limit)
+	}
+	return newResult(allowed, tokens, limit)
+}
+
+// MemoryLimiter keeps buckets in process memory, for a single instance.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// The bucket is full again at this time and can be forgotten
+	full time.Time
+}
+
+// NewMemoryLimiter creates an empty in-memory limiter.
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take takes a token from the in-memory bucket of key.
+func (l *MemoryLimiter) Take(_ context.Context, key string, limit config.RateLimit) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	every := interval(limit)
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+float64(now.Sub(b.updated))/float64(every))
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.full = now.Add(time.Duration((float64(limit.Burst) - b.tokens) * float64(every)))
+	return newResult(allowed, b.tokens, limit)
+}
+
+// sweep forgets full buckets once a minute, so memory does not grow with every identity seen.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"errors"
+
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNotEnoughCoins is returned when spending more coins than the user has.
+var ErrNotEnoughCoins = errors.New("not enough coins")
+
+// changeCoins adds amount to a user's coins in tx, or spends them when amount is negative,
+// and returns the new balance. Every coin change goes through here: the balance is changed
+// by a single conditional update, so concurrent spends cannot take it below zero.
+func changeCoins(tx *gorm.DB, userID uuid.UUID, amount int) (int, error) {
+	var user models.User
+	result := tx.Model(&user).Clauses(clause.Returning{Columns: []clause.Column{{Name: "coins"}}}).
+		Where("id = ? AND coins + ? >= 0", userID, amount).
+		Update("coins", gorm.Expr("coins + ?", amount))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Either the user does not exist or they cannot afford it
+		if err := tx.Select("id").First(&user, "id = ?", userID).Error; err != nil {
+			return 0, err
+		}
+		return 0, ErrNotEnoughCoins
+	}
+	return user.Coins, nil
+}
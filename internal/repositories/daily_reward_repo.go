@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrDailyRewardClaimed is returned when the user already claimed the reward of the day.
+var ErrDailyRewardClaimed = errors.New("daily reward already claimed")
+
+type DailyRewardRepository struct {
+	DB     *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewDailyRewardRepository(db *gorm.DB, logger *slog.Logger) *DailyRewardRepository {
+	return &DailyRewardRepository{DB: db, Logger: logger}
+}
+
+// GetLastClaim returns the user's latest daily reward claim, or nil if they never claimed one.
+func (repo *DailyRewardRepository) GetLastClaim(ctx context.Context, userID uuid.UUID) (*models.DailyRewardClaim, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var claims []models.DailyRewardClaim
+	if err := repo.DB.WithContext(ctx).Where("user_id = ?", userID).Order("day DESC").Limit(1).Find(&claims).Error; err != nil {
+		return nil, err
+	}
+	if len(claims) == 0 {
+		return nil, nil
+	}
+	return &claims[0], nil
+}
+
+// Claim stores a daily reward claim and pays its coins, returning the user's new balance.
+// The unique index on the user and day rejects a second claim for the same day, even
+// when both are sent at once.
+func (repo *DailyRewardRepository) Claim(ctx context.Context, claim *models.DailyRewardClaim) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var balance int
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(claim).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrDailyRewardClaimed
+			}
+			return err
+		}
+
+		var err error
+		balance, err = changeCoins(tx, claim.UserID, claim.Coins)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	invalidateProfiles(ctx, repo.Logger, claim.UserID)
+	return balance, nil
+}
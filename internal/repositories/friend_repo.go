@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FriendRepository struct {
+	DB *gorm.DB
+}
+
+func NewFriendRepository(db *gorm.DB) *FriendRepository {
+	return &FriendRepository{DB: db}
+}
+
+// GetFriendship returns the directed row from userID to friendID, or nil if there is none.
+func (repo *FriendRepository) GetFriendship(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var friendship models.Friendship
+	err := repo.DB.WithContext(ctx).Where("user_id = ? AND friend_id = ?", userID, friendID).First(&friendship).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &friendship, nil
+}
+
+// CreateRequest stores a pending friend request from userID to friendID.
+func (repo *FriendRepository) CreateRequest(ctx context.Context, userID, friendID uuid.UUID) (*models.Friendship, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	friendship := &models.Friendship{
+		ID:       uuid.New(),
+		UserID:   userID,
+		FriendID: friendID,
+		Status:   models.FriendshipPending,
+	}
+	if err := repo.DB.WithContext(ctx).Create(friendship).Error; err != nil {
+		return nil, err
+	}
+	return friendship, nil
+}
+
+// Accept turns a pending request from requesterID into a friendship stored in both directions.
+func (repo *FriendRepository) Accept(ctx context.Context, requesterID, receiverID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Friendship{}).
+			Where("user_id = ? AND friend_id = ? AND status = ?", requesterID, receiverID, models.FriendshipPending).
+			Update("status", models.FriendshipAccepted)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		reverse := &models.Friendship{
+			ID:       uuid.New(),
+			UserID:   receiverID,
+			FriendID: requesterID,
+			Status:   models.FriendshipAccepted,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "friend_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"status": models.FriendshipAccepted}),
+		}).Create(reverse).Error
+	})
+}
+
+// Remove deletes a friendship or request between two users in both directions.
+func (repo *FriendRepository) Remove(ctx context.Context, userID, friendID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).
+		Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)", userID, friendID, friendID, userID).
+		Delete(&models.Friendship{}).Error
+}
+
+// Block removes any friendship between the users and records that userID blocked friendID.
+func (repo *FriendRepository) Block(ctx context.Context, userID, friendID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND friend_id = ?", friendID, userID).
+			Delete(&models.Friendship{}).Error; err != nil {
+			return err
+		}
+
+		block := &models.Friendship{
+			ID:       uuid.New(),
+			UserID:   userID,
+			FriendID: friendID,
+			Status:   models.FriendshipBlocked,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "friend_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"status": models.FriendshipBlocked}),
+		}).Create(block).Error
+	})
+}
+
+// CountFriends returns how many accepted friends a user has.
+func (repo *FriendRepository) CountFriends(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var count int64
+	err := repo.DB.WithContext(ctx).Model(&models.Friendship{}).
+		Where("user_id = ? AND status = ?", userID, models.FriendshipAccepted).
+		Count(&count).Error
+	return count, err
+}
+
+// GetFriends returns the accepted friends of a user.
+func (repo *FriendRepository) GetFriends(ctx context.Context, userID uuid.UUID) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var users []models.User
+	err := repo.DB.WithContext(ctx).
+		Joins("JOIN friendships f ON f.friend_id = users.id").
+		Where("f.user_id = ? AND f.status = ?", userID, models.FriendshipAccepted).
+		Order("users.username ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// GetIncomingRequests returns the users that sent a pending friend request to userID.
+func (repo *FriendRepository) GetIncomingRequests(ctx context.Context, userID uuid.UUID) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var users []models.User
+	err := repo.DB.WithContext(ctx).
+		Joins("JOIN friendships f ON f.user_id = users.id").
+		Where("f.friend_id = ? AND f.status = ?", userID, models.FriendshipPending).
+		Order("f.created_at ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// SetContactHash stores or replaces the contact hash of a user.
+func (repo *FriendRepository) SetContactHash(ctx context.Context, userID uuid.UUID, hash string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	contact := &models.UserContactHash{UserID: userID, Hash: hash}
+	return repo.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hash"}),
+	}).Create(contact).Error
+}
+
+// FindUsersByContactHashes returns the IDs of users whose contact hash is in the list.
+func (repo *FriendRepository) FindUsersByContactHashes(ctx context.Context, hashes []string) ([]uuid.UUID, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var userIDs []uuid.UUID
+	err := repo.DB.WithContext(ctx).Model(&models.UserContactHash{}).
+		Where("hash IN ?", hashes).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"good-api/internal/models"
 
 	"github.com/google/uuid"
@@ -15,42 +16,49 @@ func NewLeaderboardRepository(db *gorm.DB) *LeaderboardRepository {
 	return &LeaderboardRepository{DB: db}
 }
 
-// We should only get the users who are competing in a tournament
-// GetGlobalLeaderboard fetches the top users globally based on level.
-func (r *LeaderboardRepository) GetGlobalLeaderboard() ([]models.User, error) {
+// GetCompetingUsers fetches every user who has competed in at least one tournament.
+// It is used to rebuild the global and country leaderboards kept in Redis.
+func (r *LeaderboardRepository) GetCompetingUsers(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var users []models.User
 
-	err := r.DB.
-		Joins("INNER JOIN tournament_participants tp ON users.id = tp.user_id").
-		Order("users.level DESC").
-		Limit(1000).
+	competing := r.DB.WithContext(ctx).Model(&models.TournamentParticipant{}).Select("user_id")
+
+	err := r.DB.WithContext(ctx).Select("id, level, country").
+		Where("id IN (?)", competing).
 		Find(&users).Error
 	return users, err
 }
 
-// We should only get the users who are competing in a tournament
-// GetCountryLeaderboard fetches the top users in a specific country based on level.
-func (r *LeaderboardRepository) GetCountryLeaderboard(country string) ([]models.User, error) {
+// GetCompetingUsersByID is GetCompetingUsers for the given users only.
+func (r *LeaderboardRepository) GetCompetingUsersByID(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var users []models.User
 
-	err := r.DB.
-		Joins("INNER JOIN tournament_participants tp ON users.id = tp.user_id").
-		Where("users.country = ?", country).
-		Order("users.level DESC").
-		Limit(1000).
+	competing := r.DB.WithContext(ctx).Model(&models.TournamentParticipant{}).Select("user_id")
+
+	err := r.DB.WithContext(ctx).Select("id, level, country").
+		Where("id IN ? AND id IN (?)", ids, competing).
 		Find(&users).Error
 	return users, err
 }
 
 // GetTournamentRank fetches a user's rank in a specific tournament.
-func (r *LeaderboardRepository) GetTournamentRank(userID uuid.UUID, tournamentID uuid.UUID) (int, error) {
+func (r *LeaderboardRepository) GetTournamentRank(ctx context.Context, userID uuid.UUID, tournamentID uuid.UUID) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var userLevel int
 
 	// ✅ Get user's level by joining `users` with `tournament_participants`
-	err := r.DB.Table("tournament_participants tp").
+	err := r.DB.WithContext(ctx).Table("tournament_participants tp").
 		Select("u.level").
 		Joins("JOIN users u ON u.id = tp.user_id").
-		Where("tp.user_id = ? AND tp.tournament_id = ?", userID, tournamentID).
+		Where("tp.user_id = ? AND tp.tournament_id = ? AND u.deleted_at IS NULL", userID, tournamentID).
 		Scan(&userLevel).Error
 	if err != nil {
 		return 0, err
@@ -59,9 +67,9 @@ func (r *LeaderboardRepository) GetTournamentRank(userID uuid.UUID, tournamentID
 	var rank int64
 
 	// ✅ Count how many users have a **higher level** in the same tournament
-	err = r.DB.Table("tournament_participants tp").
+	err = r.DB.WithContext(ctx).Table("tournament_participants tp").
 		Joins("JOIN users u ON u.id = tp.user_id").
-		Where("tp.tournament_id = ? AND u.level > ?", tournamentID, userLevel).
+		Where("tp.tournament_id = ? AND u.level > ? AND u.deleted_at IS NULL", tournamentID, userLevel).
 		Count(&rank).Error
 	if err != nil {
 		return 0, err
@@ -70,3 +78,45 @@ func (r *LeaderboardRepository) GetTournamentRank(userID uuid.UUID, tournamentID
 	// ✅ Rank = Users with higher levels + 1 (user's position)
 	return int(rank) + 1, nil
 }
+
+// GetFriendsLeaderboard ranks a user's accepted friends and the user themselves by level.
+// Everything is read with a single query using the (user_id, status) index on friendships.
+func (r *LeaderboardRepository) GetFriendsLeaderboard(ctx context.Context, userID uuid.UUID) ([]models.LeaderboardRow, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var rows []models.LeaderboardRow
+
+	friendIDs := r.DB.WithContext(ctx).Model(&models.Friendship{}).
+		Select("friend_id").
+		Where("user_id = ? AND status = ?", userID, models.FriendshipAccepted)
+
+	err := r.DB.WithContext(ctx).Model(&models.User{}).
+		Select("users.id AS user_id, users.username, users.level, users.country").
+		Where("users.id = ? OR users.id IN (?)", userID, friendIDs).
+		Order("users.level DESC, users.username ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Users with the same level share a rank
+	for i := range rows {
+		if i > 0 && rows[i].Level == rows[i-1].Level {
+			rows[i].Rank = rows[i-1].Rank
+		} else {
+			rows[i].Rank = i + 1
+		}
+	}
+	return rows, nil
+}
+
+// GetUserCountry fetches only the country of a user.
+func (r *LeaderboardRepository) GetUserCountry(ctx context.Context, userID uuid.UUID) (string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var user models.User
+	err := r.DB.WithContext(ctx).Select("country").Where("id = ?", userID).First(&user).Error
+	return user.Country, err
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository struct {
+	DB *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+// recordEvent adds a domain event to the outbox. tx must be the transaction of the change
+// the event describes, so the event is only recorded if the change is committed.
+func recordEvent(tx *gorm.DB, eventType string, aggregateID uuid.UUID, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	now := time.Now().UTC()
+	return tx.Create(&models.OutboxEvent{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     string(data),
+		CreatedAt:   now,
+		AvailableAt: now,
+	}).Error
+}
+
+// ClaimPending claims up to limit pending events, oldest first, for lease. Claimed events
+// count an attempt and are hidden from other dispatchers until the lease ends, so an event
+// whose dispatcher died is picked up again. Events that used up maxAttempts are left alone.
+func (repo *OutboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]models.OutboxEvent, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().UTC()
+	var events []models.OutboxEvent
+	err := repo.DB.WithContext(ctx).Raw(`
+		UPDATE outbox_events SET available_at = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE dispatched_at IS NULL AND attempts < ? AND available_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), maxAttempts, now, limit).Scan(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	return events, nil
+}
+
+// MarkDispatched records that an event was delivered to every sink.
+func (repo *OutboxRepository) MarkDispatched(ctx context.Context, eventID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{"dispatched_at": time.Now().UTC(), "last_error": ""}).Error
+}
+
+// MarkFailed records a failed delivery and when the event may be claimed again.
+func (repo *OutboxRepository) MarkFailed(ctx context.Context, eventID uuid.UUID, deliveryErr error, retryAt time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{"available_at": retryAt, "last_error": deliveryErr.Error()}).Error
+}
+
+// GetEvents returns the events about an aggregate, oldest first.
+func (repo *OutboxRepository) GetEvents(ctx context.Context, aggregateID uuid.UUID) ([]models.OutboxEvent, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var events []models.OutboxEvent
+	err := repo.DB.WithContext(ctx).Where("aggregate_id = ?", aggregateID).Order("created_at").Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errors returned by ShopRepository.
+var (
+	ErrItemNotFound    = errors.New("shop item not found")
+	ErrItemUnavailable = errors.New("shop item is not for sale")
+	ErrNotEnoughItems  = errors.New("not enough items in the inventory")
+)
+
+type ShopRepository struct {
+	DB     *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewShopRepository(db *gorm.DB, logger *slog.Logger) *ShopRepository {
+	return &ShopRepository{DB: db, Logger: logger}
+}
+
+// GetItems returns the shop catalog, cheapest first.
+func (repo *ShopRepository) GetItems(ctx context.Context) ([]models.ShopItem, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var items []models.ShopItem
+	err := repo.DB.WithContext(ctx).Order("price, id").Find(&items).Error
+	return items, err
+}
+
+// GetItem returns a shop item, or nil if it does not exist.
+func (repo *ShopRepository) GetItem(ctx context.Context, itemID string) (*models.ShopItem, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var items []models.ShopItem
+	if err := repo.DB.WithContext(ctx).Where("id = ?", itemID).Limit(1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return &items[0], nil
+}
+
+// UpdateItem changes the price and availability of a shop item. Nil fields are left as they are.
+func (repo *ShopRepository) UpdateItem(ctx context.Context, itemID string, price *int, available *bool) (*models.ShopItem, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	changes := map[string]interface{}{"updated_at": time.Now().UTC()}
+	if price != nil {
+		changes["price"] = *price
+	}
+	if available != nil {
+		changes["available"] = *available
+	}
+
+	var item models.ShopItem
+	result := repo.DB.WithContext(ctx).Model(&item).Clauses(clause.Returning{}).
+		Where("id = ?", itemID).
+		Updates(changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrItemNotFound
+	}
+	return &item, nil
+}
+
+// Purchase buys quantity of an item for the user at its current price. The coins are paid and
+// the items added to the inventory in one transaction.
+func (repo *ShopRepository) Purchase(ctx context.Context, userID uuid.UUID, itemID string, quantity int) (*models.Purchase, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	purchase := &models.Purchase{ItemID: itemID, Bought: quantity}
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var items []models.ShopItem
+		if err := tx.Where("id = ?", itemID).Limit(1).Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return ErrItemNotFound
+		}
+		if !items[0].Available {
+			return ErrItemUnavailable
+		}
+
+		purchase.Paid = items[0].Price * quantity
+		coins, err := changeCoins(tx, userID, -purchase.Paid)
+		if err != nil {
+			return err
+		}
+		purchase.Coins = coins
+
+		inventory := models.InventoryItem{UserID: userID, ItemID: itemID, Quantity: quantity, UpdatedAt: time.Now().UTC()}
+		err = tx.Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "item_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"quantity":   gorm.Expr("inventory_items.quantity + ?", quantity),
+					"updated_at": inventory.UpdatedAt,
+				}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "quantity"}}},
+		).Create(&inventory).Error
+		if err != nil {
+			return err
+		}
+		purchase.Quantity = inventory.Quantity
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateProfiles(ctx, repo.Logger, userID)
+	return purchase, nil
+}
+
+// GetInventory returns the items the user owns, leaving out the ones they used up.
+func (repo *ShopRepository) GetInventory(ctx context.Context, userID uuid.UUID) ([]models.InventoryItem, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var items []models.InventoryItem
+	err := repo.DB.WithContext(ctx).Where("user_id = ? AND quantity > 0", userID).Order("item_id").Find(&items).Error
+	return items, err
+}
+
+// ConsumeItem uses quantity of an item from the user's inventory. The conditional update
+// keeps concurrent uses from taking the quantity below zero.
+func (repo *ShopRepository) ConsumeItem(ctx context.Context, userID uuid.UUID, itemID string, quantity int) (*models.InventoryItem, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var item models.InventoryItem
+	result := repo.DB.WithContext(ctx).Model(&item).Clauses(clause.Returning{}).
+		Where("user_id = ? AND item_id = ? AND quantity >= ?", userID, itemID, quantity).
+		Updates(map[string]interface{}{"quantity": gorm.Expr("quantity - ?", quantity), "updated_at": time.Now().UTC()})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotEnoughItems
+	}
+	return &item, nil
+}
@@ -0,0 +1,335 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"good-api/internal/models"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrTeamFull is returned when a team has reached its member cap.
+var ErrTeamFull = errors.New("team is full")
+
+var (
+	// ErrTeamTournamentFull is returned when a team tournament has reached its team cap.
+	ErrTeamTournamentFull = errors.New("team tournament is full")
+	// ErrTeamEntryExists is returned when a team is entered into the same tournament twice.
+	ErrTeamEntryExists = errors.New("team already entered the tournament")
+)
+
+type TeamRepository struct {
+	DB     *gorm.DB
+	Logger *slog.Logger
+}
+
+func NewTeamRepository(db *gorm.DB, logger *slog.Logger) *TeamRepository {
+	return &TeamRepository{DB: db, Logger: logger}
+}
+
+// CreateTeam creates a team and adds its owner as the leader in one transaction.
+func (repo *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		team.MemberCount = 1
+		if err := tx.Create(team).Error; err != nil {
+			return err
+		}
+
+		leader := &models.TeamMember{
+			ID:       uuid.New(),
+			TeamID:   team.ID,
+			UserID:   team.OwnerID,
+			Role:     models.TeamRoleLeader,
+			JoinedAt: time.Now().UTC(),
+		}
+		return tx.Create(leader).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// Get team by ID
+func (repo *TeamRepository) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var team models.Team
+	err := repo.DB.WithContext(ctx).Where("id = ?", teamID).First(&team).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// Get team by name
+func (repo *TeamRepository) GetTeamByName(ctx context.Context, name string) (*models.Team, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var team models.Team
+	err := repo.DB.WithContext(ctx).Where("name = ?", name).First(&team).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// GetMembership returns the team membership of a user, or nil if the user has no team.
+func (repo *TeamRepository) GetMembership(ctx context.Context, userID uuid.UUID) (*models.TeamMember, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var member models.TeamMember
+	err := repo.DB.WithContext(ctx).Where("user_id = ?", userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Get all members of a team, oldest first
+func (repo *TeamRepository) GetMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var members []models.TeamMember
+	err := repo.DB.WithContext(ctx).Where("team_id = ?", teamID).Order("joined_at ASC").Find(&members).Error
+	return members, err
+}
+
+// AddMember adds a user to a team if the member cap allows it.
+// The cap is enforced by the conditional update so concurrent joins cannot overfill a team.
+func (repo *TeamRepository) AddMember(ctx context.Context, teamID, userID uuid.UUID) (*models.TeamMember, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	member := &models.TeamMember{
+		ID:       uuid.New(),
+		TeamID:   teamID,
+		UserID:   userID,
+		Role:     models.TeamRoleMember,
+		JoinedAt: time.Now().UTC(),
+	}
+
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Team{}).
+			Where("id = ? AND member_count < max_members", teamID).
+			Update("member_count", gorm.Expr("member_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTeamFull
+		}
+		return tx.Create(member).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a user from a team and decrements the member count.
+func (repo *TeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("team_id = ? AND user_id = ?", teamID, userID).Delete(&models.TeamMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("user %s is not a member of team %s", userID, teamID)
+		}
+		return tx.Model(&models.Team{}).
+			Where("id = ?", teamID).
+			Update("member_count", gorm.Expr("member_count - 1")).Error
+	})
+}
+
+// Update a member's role
+func (repo *TeamRepository) UpdateMemberRole(ctx context.Context, teamID, userID uuid.UUID, role string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Where("team_id = ? AND user_id = ?", teamID, userID).
+		Update("role", role).Error
+}
+
+// TransferLeadership hands the leader role to another member and updates the team owner.
+func (repo *TeamRepository) TransferLeadership(ctx context.Context, teamID, oldLeaderID, newLeaderID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TeamMember{}).
+			Where("team_id = ? AND user_id = ?", teamID, oldLeaderID).
+			Update("role", models.TeamRoleOfficer).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TeamMember{}).
+			Where("team_id = ? AND user_id = ?", teamID, newLeaderID).
+			Update("role", models.TeamRoleLeader).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Team{}).
+			Where("id = ?", teamID).
+			Update("owner_id", newLeaderID).Error
+	})
+}
+
+// Delete a team together with its memberships
+func (repo *TeamRepository) DeleteTeam(ctx context.Context, teamID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.TeamMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Team{}, "id = ?", teamID).Error
+	})
+}
+
+// Fetch an active team tournament that still has room for another team
+func (repo *TeamRepository) GetActiveTeamTournament(ctx context.Context) (*models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var tournament models.Tournament
+	err := repo.DB.WithContext(ctx).Where("is_active = ? AND mode = ? AND user_count < max_users", true, models.TournamentModeTeam).
+		First(&tournament).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// Create a new team tournament for the current UTC day
+func (repo *TeamRepository) NewTeamTournament(ctx context.Context, maxTeams int) (*models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var count int64
+	if err := repo.DB.WithContext(ctx).Model(&models.Tournament{}).Where("mode = ?", models.TournamentModeTeam).Count(&count).Error; err != nil {
+		// Only the name depends on the count
+		repo.Logger.WarnContext(ctx, "failed to count team tournaments", "error", err)
+	}
+	startTime := models.UTCDay(time.Now())
+	endTime := startTime.Add(23*time.Hour + 59*time.Minute)
+
+	tournament := &models.Tournament{
+		ID:        uuid.New(),
+		Name:      fmt.Sprintf("team_tournament_%d", count+1),
+		StartTime: startTime,
+		EndTime:   endTime,
+		IsActive:  true,
+		UserCount: 0,
+		MaxUsers:  maxTeams,
+		Mode:      models.TournamentModeTeam,
+	}
+
+	if err := repo.DB.WithContext(ctx).Create(tournament).Error; err != nil {
+		return nil, err
+	}
+	return tournament, nil
+}
+
+// GetActiveEntry returns the entry of a team in an active team tournament, or nil.
+func (repo *TeamRepository) GetActiveEntry(ctx context.Context, teamID uuid.UUID) (*models.TeamTournamentEntry, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var entry models.TeamTournamentEntry
+	err := repo.DB.WithContext(ctx).Table("team_tournament_entries e").
+		Select("e.*").
+		Joins("JOIN tournaments t ON t.id = e.tournament_id").
+		Where("e.team_id = ? AND t.is_active = ?", teamID, true).
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetActiveEntries returns the entries of every team in an active team tournament.
+func (repo *TeamRepository) GetActiveEntries(ctx context.Context) ([]models.TeamTournamentEntry, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var entries []models.TeamTournamentEntry
+	err := repo.DB.WithContext(ctx).Table("team_tournament_entries e").
+		Select("e.*").
+		Joins("JOIN tournaments t ON t.id = e.tournament_id").
+		Where("t.is_active = ?", true).
+		Find(&entries).Error
+	return entries, err
+}
+
+// AddTeamEntry registers a team in a team tournament and increases the tournament's team count.
+// It returns ErrTeamTournamentFull when the tournament filled up in the meantime.
+func (repo *TeamRepository) AddTeamEntry(ctx context.Context, tournamentID, teamID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Tournament{}).
+			Where("id = ? AND user_count < max_users", tournamentID).
+			Update("user_count", gorm.Expr("user_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTeamTournamentFull
+		}
+
+		entry := &models.TeamTournamentEntry{
+			ID:           uuid.New(),
+			TournamentID: tournamentID,
+			TeamID:       teamID,
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrTeamEntryExists
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Persist the final score of a team in a team tournament
+func (repo *TeamRepository) UpdateEntryScore(ctx context.Context, tournamentID, teamID uuid.UUID, score int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Model(&models.TeamTournamentEntry{}).
+		Where("tournament_id = ? AND team_id = ?", tournamentID, teamID).
+		Update("score", score).Error
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// QueryTimeout bounds every repository call, including all queries of a transaction,
+// so a slow query fails fast even when the caller's context has no deadline.
+// main sets it from the database.query_timeout setting.
+var QueryTimeout = 5 * time.Second
+
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, QueryTimeout)
+}
@@ -1,30 +1,36 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"good-api/internal/models"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TournamentRepository struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Logger *slog.Logger
 }
 
-func NewTournamentRepository(db *gorm.DB) *TournamentRepository {
-	return &TournamentRepository{DB: db}
+func NewTournamentRepository(db *gorm.DB, logger *slog.Logger) *TournamentRepository {
+	return &TournamentRepository{DB: db, Logger: logger}
 }
 
-// Create a new tournament
-func (repo *TournamentRepository) NewTournament() (*models.Tournament, error) {
+// Create a new tournament with room for maxUsers players
+func (repo *TournamentRepository) NewTournament(ctx context.Context, maxUsers int) (*models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var count int64
-	repo.DB.Model(&models.Tournament{}).Count(&count) // Count existing tournaments
-	startTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.UTC)
-	endTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 23, 59, 0, 0, time.UTC)
+	repo.DB.WithContext(ctx).Model(&models.Tournament{}).Count(&count) // Count existing tournaments
+	startTime := models.UTCDay(time.Now())
+	endTime := startTime.Add(23*time.Hour + 59*time.Minute)
 
 	tournament := &models.Tournament{
 		ID:        uuid.New(),
@@ -33,90 +39,221 @@ func (repo *TournamentRepository) NewTournament() (*models.Tournament, error) {
 		EndTime:   endTime,
 		IsActive:  true,
 		UserCount: 0,
-		MaxUsers:  35,
+		MaxUsers:  maxUsers,
+		Mode:      models.TournamentModeSolo,
 	}
 
-	if err := repo.DB.Create(tournament).Error; err != nil {
+	if err := repo.DB.WithContext(ctx).Create(tournament).Error; err != nil {
 		return nil, err
 	}
 	return tournament, nil
 }
 
 // Fetch an active tournament
-func (repo *TournamentRepository) GetActiveTournament() (*models.Tournament, error) {
+func (repo *TournamentRepository) GetActiveTournament(ctx context.Context) (*models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var tournament models.Tournament
-	err := repo.DB.Where("is_active = ? AND user_count < ?", true, 35).First(&tournament).Error
+	err := repo.DB.WithContext(ctx).Where("is_active = ? AND user_count < max_users AND mode = ?", true, models.TournamentModeSolo).First(&tournament).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
 	return &tournament, err
 }
 
-// Get user's tournament
-func (repo *TournamentRepository) GetUserTournament(userID uuid.UUID) (*models.Tournament, error) {
-	var participant models.TournamentParticipant
-	err := repo.DB.Where("user_id = ?", userID).First(&participant).Error
+// Get the active tournament the user is in, or nil if the user is not in one.
+// Participations in finished tournaments are kept, so they are skipped here.
+func (repo *TournamentRepository) GetUserTournament(ctx context.Context, userID uuid.UUID) (*models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var tournament models.Tournament
+	err := repo.DB.WithContext(ctx).
+		Joins("JOIN tournament_participants ON tournament_participants.tournament_id = tournaments.id").
+		Where("tournament_participants.user_id = ? AND tournaments.is_active = ?", userID, true).
+		First(&tournament).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-
-	var tournament models.Tournament
-	err = repo.DB.Where("id = ?", participant.TournamentID).First(&tournament).Error
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println("Looking up tournament participant for user: ", userID)
+	repo.Logger.DebugContext(ctx, "found tournament of user", "user_id", userID, "tournament_id", tournament.ID)
 	return &tournament, nil
 }
 
-// Add a participant to a tournament
-func (repo *TournamentRepository) AddParticipant(tournamentID, userID uuid.UUID) error {
-	var user models.User
+// Add a participant to a tournament for an entry fee and record the entry in the outbox.
+// The fee is paid in the same transaction, so a user cannot enter without paying or pay twice.
+func (repo *TournamentRepository) AddParticipant(ctx context.Context, tournamentID, userID uuid.UUID, fee int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 
-	// Get the current level
-	if err := repo.DB.First(&user, "id = ?", userID).Error; err != nil {
-		return fmt.Errorf("failed to find user for level info: %w", err)
-	}
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
 
-	// Create participant with user's level
-	participant := &models.TournamentParticipant{
-		ID:           uuid.New(),
-		UserID:       userID,
-		TournamentID: tournamentID,
-		Level:        user.Level,
-	}
+		// Get the current level
+		if err := tx.First(&user, "id = ?", userID).Error; err != nil {
+			return fmt.Errorf("failed to find user for level info: %w", err)
+		}
 
-	if err := repo.DB.Create(participant).Error; err != nil {
+		if _, err := changeCoins(tx, userID, -fee); err != nil {
+			return err
+		}
+
+		// Create participant with user's level
+		participant := &models.TournamentParticipant{
+			ID:           uuid.New(),
+			UserID:       userID,
+			TournamentID: tournamentID,
+			Level:        user.Level,
+		}
+
+		if err := tx.Create(participant).Error; err != nil {
+			return err
+		}
+
+		// Increase tournament user count
+		err := tx.Model(&models.Tournament{}).
+			Where("id = ?", tournamentID).
+			Update("user_count", gorm.Expr("user_count + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(tx, models.EventTournamentEntered, tournamentID, models.TournamentEnteredEvent{
+			TournamentID: tournamentID,
+			UserID:       userID,
+			Level:        user.Level,
+		})
+	})
+	if err != nil {
 		return err
 	}
-
-	// Increase tournament user count
-	return repo.DB.Model(&models.Tournament{}).
-		Where("id = ?", tournamentID).
-		Update("user_count", gorm.Expr("user_count + 1")).Error
+	invalidateProfiles(ctx, repo.Logger, userID)
+	return nil
 }
 
 // Increase user score in a tournament
-func (repo *TournamentRepository) IncreaseUserLevel(tournamentID, userID uuid.UUID) error {
-	return repo.DB.Model(&models.TournamentParticipant{}).
+func (repo *TournamentRepository) IncreaseUserLevel(ctx context.Context, tournamentID, userID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return increaseUserLevel(tx, tournamentID, userID)
+	})
+}
+
+// increaseUserLevel raises the user's tournament level and records it in the outbox.
+func increaseUserLevel(tx *gorm.DB, tournamentID, userID uuid.UUID) error {
+	var participant models.TournamentParticipant
+	err := tx.Model(&participant).Clauses(clause.Returning{}).
 		Where("tournament_id = ? AND user_id = ?", tournamentID, userID).
 		Update("level", gorm.Expr("level + 1")).Error
+	if err != nil {
+		return err
+	}
+
+	return recordEvent(tx, models.EventLevelIncreased, userID, models.LevelIncreasedEvent{
+		TournamentID: tournamentID,
+		UserID:       userID,
+		Level:        participant.Level,
+	})
+}
+
+// CompleteLevel increases the user's score in a tournament for a completed level
+// and records the level in the outbox.
+func (repo *TournamentRepository) CompleteLevel(ctx context.Context, tournamentID, userID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var participant models.TournamentParticipant
+		err := tx.Model(&participant).Clauses(clause.Returning{}).
+			Where("tournament_id = ? AND user_id = ?", tournamentID, userID).
+			Update("level", gorm.Expr("level + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(tx, models.EventLevelCompleted, userID, models.LevelCompletedEvent{
+			UserID:       userID,
+			TournamentID: &tournamentID,
+			Level:        participant.Level,
+		})
+	})
+}
+
+// ErrRewardAlreadyPaid is returned when the user was already paid a reward for the tournament.
+var ErrRewardAlreadyPaid = errors.New("tournament reward already paid")
+
+// ErrRewardUserNotFound is returned when the rewarded user no longer exists, because they deleted their account.
+var ErrRewardUserNotFound = errors.New("rewarded user not found")
+
+// PayReward adds a tournament reward to the user's coins, levels the user up in the tournament
+// if levelUp is set, and records the payment in the outbox. The reward row written with the
+// coins makes the payment idempotent: paying a user again returns ErrRewardAlreadyPaid.
+// Paying a deleted user returns ErrRewardUserNotFound and records nothing.
+func (repo *TournamentRepository) PayReward(ctx context.Context, reward models.RewardPaidEvent, levelUp bool) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		paid := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.TournamentReward{
+			ID:           uuid.New(),
+			TournamentID: reward.TournamentID,
+			UserID:       reward.UserID,
+			TeamID:       reward.TeamID,
+			Rank:         reward.Rank,
+			Coins:        reward.Coins,
+			PaidAt:       time.Now().UTC(),
+		})
+		if paid.Error != nil {
+			return paid.Error
+		}
+		if paid.RowsAffected == 0 {
+			return ErrRewardAlreadyPaid
+		}
+
+		if _, err := changeCoins(tx, reward.UserID, reward.Coins); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRewardUserNotFound
+			}
+			return err
+		}
+		if levelUp {
+			if err := increaseUserLevel(tx, reward.TournamentID, reward.UserID); err != nil {
+				return err
+			}
+		}
+		return recordEvent(tx, models.EventRewardPaid, reward.TournamentID, reward)
+	})
+	if err != nil {
+		return err
+	}
+	invalidateProfiles(ctx, repo.Logger, reward.UserID)
+	return nil
 }
 
 // Update user coins
-func (repo *TournamentRepository) UpdateUserCoins(userID uuid.UUID, coins int) error {
-	return repo.DB.Model(&models.User{}).
-		Where("id = ?", userID).
-		Update("coins", gorm.Expr("coins + ?", coins)).Error
+func (repo *TournamentRepository) UpdateUserCoins(ctx context.Context, userID uuid.UUID, coins int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	if _, err := changeCoins(repo.DB.WithContext(ctx), userID, coins); err != nil {
+		return err
+	}
+	invalidateProfiles(ctx, repo.Logger, userID)
+	return nil
 }
 
 // Get tournament by ID
-func (repo *TournamentRepository) GetTournamentByID(tournamentID uuid.UUID) (*models.Tournament, error) {
+func (repo *TournamentRepository) GetTournamentByID(ctx context.Context, tournamentID uuid.UUID) (*models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var tournament models.Tournament
-	err := repo.DB.Where("id = ?", tournamentID).First(&tournament).Error
+	err := repo.DB.WithContext(ctx).Where("id = ?", tournamentID).First(&tournament).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -124,42 +261,61 @@ func (repo *TournamentRepository) GetTournamentByID(tournamentID uuid.UUID) (*mo
 }
 
 // Get all tournaments (ordered by start time)
-func (repo *TournamentRepository) GetAllTournaments() ([]models.Tournament, error) {
+func (repo *TournamentRepository) GetAllTournaments(ctx context.Context) ([]models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var tournaments []models.Tournament
 
 	if repo.DB == nil {
-		fmt.Println("Error: repo.DB is nil")
 		return nil, errors.New("database connection is nil")
 	}
 
-	fmt.Println("Running SQL Query: SELECT * FROM tournaments ORDER BY start_time DESC")
-
-	err := repo.DB.Order("start_time DESC").Find(&tournaments).Error
+	err := repo.DB.WithContext(ctx).Order("start_time DESC").Find(&tournaments).Error
 
 	if err != nil {
-		log.Printf("Error fetching tournaments: %v", err)
+		repo.Logger.ErrorContext(ctx, "failed to fetch tournaments", "error", err)
 		return nil, err
 	}
 
-	if len(tournaments) == 0 {
-		log.Println("No tournaments found in the database")
-	}
-	fmt.Println("Successfully fetched tournaments: ", tournaments)
+	repo.Logger.DebugContext(ctx, "fetched tournaments", "count", len(tournaments))
 	return tournaments, nil
 }
 
-// Finish a tournament
-func (repo *TournamentRepository) FinishTournament(tournamentID uuid.UUID) error {
-	return repo.DB.Model(&models.Tournament{}).
-		Where("id = ?", tournamentID).
-		Update("is_active", false).Error
+// Finish a tournament and record it in the outbox
+func (repo *TournamentRepository) FinishTournament(ctx context.Context, tournamentID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tournament models.Tournament
+		err := tx.Model(&tournament).Clauses(clause.Returning{}).
+			Where("id = ? AND is_active = ?", tournamentID, true).
+			Update("is_active", false).Error
+		if err != nil {
+			return err
+		}
+		// Already finished, the event was recorded then
+		if tournament.ID == uuid.Nil {
+			return nil
+		}
+
+		return recordEvent(tx, models.EventTournamentFinished, tournamentID, models.TournamentFinishedEvent{
+			TournamentID: tournamentID,
+			Mode:         tournament.Mode,
+			Participants: tournament.UserCount,
+		})
+	})
 }
 
 // Get top 1000 players across all tournaments (global ranking)
-func (repo *TournamentRepository) GetTopGlobalPlayers() ([]models.User, error) {
+func (repo *TournamentRepository) GetTopGlobalPlayers(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var users []models.User
 
-	err := repo.DB.
+	err := repo.DB.WithContext(ctx).
 		Joins("INNER JOIN tournament_participants tp ON users.id = tp.user_id").
 		Order("users.level DESC").
 		Limit(1000).
@@ -171,3 +327,17 @@ func (repo *TournamentRepository) GetTopGlobalPlayers() ([]models.User, error) {
 
 	return users, nil
 }
+
+// GetActiveTournamentStats counts active tournaments and their occupied and total seats per mode.
+func (repo *TournamentRepository) GetActiveTournamentStats(ctx context.Context) ([]models.TournamentStats, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var stats []models.TournamentStats
+	err := repo.DB.WithContext(ctx).Model(&models.Tournament{}).
+		Select("mode, COUNT(*) AS active, COALESCE(SUM(user_count), 0) AS participants, COALESCE(SUM(max_users), 0) AS seats").
+		Where("is_active = ?", true).
+		Group("mode").
+		Scan(&stats).Error
+	return stats, err
+}
@@ -1,12 +1,19 @@
 package repositories
 
 import (
+	"context"
 	"errors"
+	"good-api/internal/cache"
+	"good-api/internal/metrics"
 	"good-api/internal/models"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 /*
@@ -23,96 +30,534 @@ This struct holds a database connection
 We use a pointer (*gorm.DB) so wedon't copy the database object every time.
 */
 type UserRepository struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Logger *slog.Logger
+	// How long GetUserByID caches profiles in Redis; 0 reads Postgres every time
+	ProfileTTL time.Duration
+	loads      singleflight.Group
 }
 
 // This function initializes the repository and stores the db connection inside it.
-func NewUserRepository(db *gorm.DB) *UserRepository {
-	return &UserRepository{DB: db}
+func NewUserRepository(db *gorm.DB, logger *slog.Logger) *UserRepository {
+	return &UserRepository{DB: db, Logger: logger}
 }
 
-// Create a user
-func (repo *UserRepository) CreateUser(user *models.User) (*models.User, error) {
-	if err := repo.DB.Create(user).Error; err != nil {
+// NewCachedUserRepository creates a repository that caches the profiles read by GetUserByID for ttl.
+func NewCachedUserRepository(db *gorm.DB, logger *slog.Logger, ttl time.Duration) *UserRepository {
+	return &UserRepository{DB: db, Logger: logger, ProfileTTL: ttl}
+}
+
+// Errors returned when a username cannot be used.
+var (
+	ErrUsernameTaken    = errors.New("username is taken")
+	ErrUsernameHeld     = errors.New("username is held for its previous owner")
+	ErrUsernameCooldown = errors.New("username was changed too recently")
+)
+
+// Errors returned when lives cannot be used or bought.
+var (
+	ErrNoLives   = errors.New("no lives left")
+	ErrLivesFull = errors.New("lives are already full")
+)
+
+// Create a user. The username must not be used by another user or held for its previous owner.
+func (repo *UserRepository) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrUsernameTaken
+			}
+			return err
+		}
+		return checkUsernameHold(tx, user.ID, user.Username)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-// Find a user by ID
-func (repo *UserRepository) GetUserByID(userID uuid.UUID) (*models.User, error) {
+// ChangeUsername renames a user for cost coins. It fails if the user changed their username
+// less than cooldown ago, and holds the old username for the user for hold.
+func (repo *UserRepository) ChangeUsername(ctx context.Context, userID uuid.UUID, username string, cost int, cooldown, hold time.Duration) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var user models.User
-	if err := repo.DB.First(&user, "id = ?", userID).Error; err != nil {
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The lock keeps concurrent changes of the same user from both passing the cooldown
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		var last models.UsernameChange
+		if err := tx.Where("user_id = ?", userID).Order("changed_at DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+		if last.ID != uuid.Nil && now.Before(last.ChangedAt.Add(cooldown)) {
+			return ErrUsernameCooldown
+		}
+		change := &models.UsernameChange{
+			ID:          uuid.New(),
+			UserID:      userID,
+			OldUsername: user.Username,
+			NewUsername: username,
+			Cost:        cost,
+			ChangedAt:   now,
+			HeldUntil:   now.Add(hold),
+		}
+
+		// The unique index makes this wait for any transaction that is using or giving up
+		// the username, so the hold check below sees the hold of a concurrent change
+		if err := tx.Model(&user).Update("username", username).Error; err != nil {
+			if isUniqueViolation(err) {
+				return ErrUsernameTaken
+			}
+			return err
+		}
+		if err := checkUsernameHold(tx, userID, username); err != nil {
+			return err
+		}
+		if user.Coins, err = changeCoins(tx, userID, -cost); err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	})
+	if err != nil {
 		return nil, err
 	}
+	invalidateProfiles(ctx, repo.Logger, userID)
 	return &user, nil
 }
 
+// checkUsernameHold fails if username is held for a user other than userID.
+// Names are compared ignoring case, like the unique index does.
+func checkUsernameHold(tx *gorm.DB, userID uuid.UUID, username string) error {
+	var held int64
+	err := tx.Model(&models.UsernameChange{}).
+		Where("lower(old_username) = lower(?) AND held_until > ? AND user_id <> ?", username, time.Now().UTC(), userID).
+		Count(&held).Error
+	if err != nil {
+		return err
+	}
+	if held > 0 {
+		return ErrUsernameHeld
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a unique index violation from Postgres.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// GetUserByID finds a user by ID. With a profile TTL, the user is read from the Redis cache
+// and concurrent misses for the same user share a single query.
+func (repo *UserRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	if repo.ProfileTTL <= 0 {
+		return repo.loadUser(ctx, userID)
+	}
+
+	user, found, err := cache.GetUserProfile(ctx, userID)
+	switch {
+	case err != nil:
+		// Postgres still answers while Redis is down
+		metrics.ProfileCacheLookups.WithLabelValues("error").Inc()
+		repo.Logger.WarnContext(ctx, "failed to read cached user profile", "user_id", userID, "error", err)
+	case found:
+		metrics.ProfileCacheLookups.WithLabelValues("hit").Inc()
+		return user, nil
+	default:
+		metrics.ProfileCacheLookups.WithLabelValues("miss").Inc()
+	}
+
+	loaded := repo.loads.DoChan(userID.String(), func() (interface{}, error) {
+		// The query is shared, so it must not fail because the caller that started it gave up
+		loadCtx := context.WithoutCancel(ctx)
+		user, err := repo.loadUser(loadCtx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if err := cache.SetUserProfile(loadCtx, user, repo.ProfileTTL); err != nil {
+			repo.Logger.WarnContext(ctx, "failed to cache user profile", "user_id", userID, "error", err)
+		}
+		return user, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-loaded:
+		if result.Shared {
+			metrics.ProfileCacheSharedLoads.Inc()
+		}
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		// Every caller gets its own copy to change
+		user := *result.Val.(*models.User)
+		return &user, nil
+	}
+}
+
+func (repo *UserRepository) loadUser(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var user models.User
+	if err := repo.DB.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUsersByIDs fetches several users in a single query, keyed by ID. IDs without a user are left out.
+func (repo *UserRepository) GetUsersByIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	users := make(map[uuid.UUID]models.User, len(userIDs))
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	var found []models.User
+	if err := repo.DB.WithContext(ctx).Where("id IN ?", userIDs).Find(&found).Error; err != nil {
+		return nil, err
+	}
+
+	for _, user := range found {
+		users[user.ID] = user
+	}
+	return users, nil
+}
+
 // GetUserByUsername fetches a user by their username
-func (repo *UserRepository) GetUserByUsername(username string) (*models.User, error) {
+func (repo *UserRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var user models.User
-	if err := repo.DB.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := repo.DB.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, err
 		}
-		log.Printf("unexpected error fetching user: %v", err)
+		repo.Logger.ErrorContext(ctx, "unexpected error fetching user", "error", err)
 		return nil, err
 	}
 	return &user, nil
 }
 
 // GetAllUsers retrieves all users from the database
-func (repo *UserRepository) GetAllUsers() ([]models.User, error) {
+func (repo *UserRepository) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var users []models.User
-	err := repo.DB.Find(&users).Error
+	err := repo.DB.WithContext(ctx).Find(&users).Error
 	return users, err
 }
 
-// Update a User
-func (repo *UserRepository) UpdateUser(user *models.User) (*models.User, error) {
-	if err := repo.DB.Save(user).Error; err != nil {
-		return nil, err
+// UpdateUser writes the editable profile fields of a user and returns the stored user.
+// Coins, lives and level are not written: they only change through their own updates,
+// which a profile edit based on an older read would otherwise revert.
+func (repo *UserRepository) UpdateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var updated models.User
+	result := repo.DB.WithContext(ctx).Model(&updated).Clauses(clause.Returning{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]interface{}{"country": user.Country})
+	if result.Error != nil {
+		return nil, result.Error
 	}
-	return user, nil
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	invalidateProfiles(ctx, repo.Logger, user.ID)
+	return &updated, nil
 }
 
-// Delete a User
-func (repo *UserRepository) DeleteUser(userID uuid.UUID) error {
-	return repo.DB.Delete(&models.User{}, userID).Error // Deletes the user by id.
+// DeleteUser soft deletes a user and drops their friendships and contact hash, which would
+// otherwise show the deleted user to others. The user's tournament results are anonymized.
+func (repo *UserRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? OR friend_id = ?", userID, userID).Delete(&models.Friendship{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserContactHash{}).Error; err != nil {
+			return err
+		}
+		if err := anonymizeResults(tx, userID); err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, "id = ?", userID).Error // Sets deleted_at
+	})
+	if err != nil {
+		return err
+	}
+	invalidateProfiles(ctx, repo.Logger, userID)
+	return nil
 }
 
-// AddCoins adds coins to a user's balance.
-func (repo *UserRepository) AddCoins(userID uuid.UUID, amount int) error {
-	var user models.User
-	if err := repo.DB.First(&user, "id = ?", userID).Error; err != nil {
+// anonymizeResults moves a user's tournament results to random user IDs. They are kept for the
+// rankings of past tournaments, but can no longer be linked to the user.
+func anonymizeResults(tx *gorm.DB, userID uuid.UUID) error {
+	err := tx.Model(&models.TournamentParticipant{}).
+		Where("user_id = ?", userID).
+		Update("user_id", gorm.Expr("uuid_generate_v4()")).Error
+	if err != nil {
 		return err
 	}
+	return tx.Model(&models.TournamentReward{}).
+		Where("user_id = ?", userID).
+		Update("user_id", gorm.Expr("uuid_generate_v4()")).Error
+}
+
+// EraseDeletedUsers permanently removes up to limit users that were deleted before the cutoff,
+// with the data that was kept for them. It returns how many users were erased.
+func (repo *UserRepository) EraseDeletedUsers(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var userIDs []uuid.UUID
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&models.User{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("deleted_at < ?", deletedBefore).
+			Order("deleted_at").
+			Limit(limit).
+			Pluck("id", &userIDs).Error
+		if err != nil || len(userIDs) == 0 {
+			return err
+		}
+
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.TeamMember{}).Error; err != nil {
+			return err
+		}
+		// Also releases the usernames still held for the users
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.UsernameChange{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.InventoryItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", userIDs).Delete(&models.DailyRewardClaim{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", userIDs).Delete(&models.User{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(userIDs), nil
+}
+
+// ExportUser collects everything stored about a user in Postgres.
+func (repo *UserRepository) ExportUser(ctx context.Context, userID uuid.UUID) (*models.UserExport, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 
-	user.Coins += amount
+	export := &models.UserExport{ExportedAt: time.Now().UTC()}
+	// A deleted user's account is kept until it is erased, so it can still be exported.
+	// Their tournament results were anonymized at deletion and are no longer part of it.
+	db := repo.DB.WithContext(ctx).Unscoped()
+	if err := db.First(&export.User, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
 
-	if err := repo.DB.Save(&user).Error; err != nil {
-		return errors.New("failed to update user balance")
+	if err := db.Where("user_id = ?", userID).Find(&export.Participations).Error; err != nil {
+		return nil, err
+	}
+	tournamentIDs := make([]uuid.UUID, len(export.Participations))
+	for i, participation := range export.Participations {
+		tournamentIDs[i] = participation.TournamentID
+	}
+	export.Tournaments = []models.Tournament{}
+	if len(tournamentIDs) > 0 {
+		if err := db.Where("id IN ?", tournamentIDs).Order("start_time").Find(&export.Tournaments).Error; err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	if err := db.Where("user_id = ?", userID).Order("paid_at").Find(&export.Rewards).Error; err != nil {
+		return nil, err
+	}
+
+	var membership models.TeamMember
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&membership).Error; err != nil {
+		return nil, err
+	}
+	if membership.ID != uuid.Nil {
+		export.TeamMembership = &membership
+	}
+
+	if err := db.Where("user_id = ? OR friend_id = ?", userID, userID).Order("created_at").Find(&export.Friendships).Error; err != nil {
+		return nil, err
+	}
+
+	var contact models.UserContactHash
+	if err := db.Where("user_id = ?", userID).Limit(1).Find(&contact).Error; err != nil {
+		return nil, err
+	}
+	if contact.UserID != uuid.Nil {
+		export.ContactHash = &contact
+	}
+
+	// Events about the user, or about a tournament with the user in their payload
+	err := db.Where("aggregate_id = ? OR payload->>'user_id' = ?", userID, userID.String()).
+		Order("created_at").
+		Find(&export.Events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Where("user_id = ?", userID).Order("changed_at").Find(&export.UsernameHistory).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("item_id").Find(&export.Inventory).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Where("user_id = ?", userID).Order("day").Find(&export.DailyRewardClaims).Error; err != nil {
+		return nil, err
+	}
+	return export, nil
 }
 
-func (repo *UserRepository) GetUserTournament(userID uuid.UUID) (*models.Tournament, error) {
-	var participant models.TournamentParticipant
+// LevelUp increases the user's level, adds the level-up coins and records the level in the outbox.
+func (repo *UserRepository) LevelUp(ctx context.Context, userID uuid.UUID, coins int) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var user models.User
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&user).Clauses(clause.Returning{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{"level": gorm.Expr("level + 1"), "coins": gorm.Expr("coins + ?", coins)}).Error
+		if err != nil {
+			return err
+		}
+		if user.ID == uuid.Nil {
+			return gorm.ErrRecordNotFound
+		}
 
-	// Check if user is in a tournament
-	err := repo.DB.Where("user_id = ?", userID).First(&participant).Error
+		return recordEvent(tx, models.EventLevelCompleted, userID, models.LevelCompletedEvent{
+			UserID: userID,
+			Level:  user.Level,
+			Coins:  coins,
+		})
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
+		return nil, err
+	}
+	invalidateProfiles(ctx, repo.Logger, userID)
+	return &user, nil
+}
+
+// AddCoins adds coins to a user's balance, or spends them when amount is negative.
+func (repo *UserRepository) AddCoins(ctx context.Context, userID uuid.UUID, amount int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	if _, err := changeCoins(repo.DB.WithContext(ctx), userID, amount); err != nil {
+		return err
+	}
+	invalidateProfiles(ctx, repo.Logger, userID)
+	return nil
+}
+
+// LoseLife takes one life from the user, after adding the lives regenerated since their last change.
+func (repo *UserRepository) LoseLife(ctx context.Context, userID uuid.UUID, maxLives int, regen time.Duration) (*models.User, error) {
+	return repo.updateLives(ctx, userID, maxLives, regen, func(_ *gorm.DB, user *models.User) error {
+		if user.Lives <= 0 {
+			return ErrNoLives
+		}
+		user.Lives--
+		return nil
+	})
+}
+
+// RefillLives gives the user all their lives back for cost coins.
+func (repo *UserRepository) RefillLives(ctx context.Context, userID uuid.UUID, maxLives int, regen time.Duration, cost int) (*models.User, error) {
+	return repo.updateLives(ctx, userID, maxLives, regen, func(tx *gorm.DB, user *models.User) error {
+		if user.Lives >= maxLives {
+			return ErrLivesFull
+		}
+		coins, err := changeCoins(tx, user.ID, -cost)
+		if err != nil {
+			return err
+		}
+		user.Coins = coins
+		user.Lives = maxLives
+		return nil
+	})
+}
+
+// updateLives applies change to the user's lives with the row locked, so concurrent changes
+// cannot spend the same life twice. The regenerated lives are counted in before change runs.
+func (repo *UserRepository) updateLives(ctx context.Context, userID uuid.UUID, maxLives int, regen time.Duration, change func(tx *gorm.DB, user *models.User) error) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var user models.User
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		user.Lives, user.LivesUpdatedAt = models.RegenerateLives(user.Lives, user.LivesUpdatedAt, now, maxLives, regen)
+		if err := change(tx, &user); err != nil {
+			return err
 		}
+		if user.Lives >= maxLives {
+			// Full lives do not regenerate; the clock starts again with the next lost life
+			user.LivesUpdatedAt = now
+		}
+		return tx.Model(&user).Select("lives", "lives_updated_at").Updates(&user).Error
+	})
+	if err != nil {
 		return nil, err
 	}
+	invalidateProfiles(ctx, repo.Logger, userID)
+	return &user, nil
+}
+
+// invalidateProfiles drops cached user profiles after their rows changed. It runs even when
+// this instance does not cache profiles, because other instances may. A failure is only
+// logged: the write went through, and the stale profile expires with its TTL.
+func invalidateProfiles(ctx context.Context, logger *slog.Logger, userIDs ...uuid.UUID) {
+	if err := cache.InvalidateUserProfiles(context.WithoutCancel(ctx), userIDs...); err != nil {
+		logger.ErrorContext(ctx, "failed to invalidate cached user profiles", "user_ids", userIDs, "error", err)
+	}
+}
+
+// GetUserTournament returns the active tournament the user is in, or nil if the user is not in one.
+// Participations in finished tournaments are kept, so they are skipped here.
+func (repo *UserRepository) GetUserTournament(ctx context.Context, userID uuid.UUID) (*models.Tournament, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 
 	var tournament models.Tournament
-	err = repo.DB.Where("id = ? ", participant.TournamentID).First(&tournament).Error
+	err := repo.DB.WithContext(ctx).
+		Joins("JOIN tournament_participants ON tournament_participants.tournament_id = tournaments.id").
+		Where("tournament_participants.user_id = ? AND tournaments.is_active = ?", userID, true).
+		First(&tournament).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	return &tournament, err
+	return &tournament, nil
 }
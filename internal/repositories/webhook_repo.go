@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+// CreateSubscription stores a new webhook subscription.
+func (repo *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Create(subscription).Error
+}
+
+// GetSubscriptions returns every webhook subscription, oldest first.
+func (repo *WebhookRepository) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var subscriptions []models.WebhookSubscription
+	err := repo.DB.WithContext(ctx).Order("created_at").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// GetSubscription returns a webhook subscription, or nil if it does not exist.
+func (repo *WebhookRepository) GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*models.WebhookSubscription, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var subscriptions []models.WebhookSubscription
+	if err := repo.DB.WithContext(ctx).Where("id = ?", subscriptionID).Limit(1).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+	return &subscriptions[0], nil
+}
+
+// DeleteSubscription removes a subscription and its pending deliveries. Dead letters are kept.
+// It returns false if the subscription does not exist.
+func (repo *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var deleted bool
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.WebhookSubscription{}, "id = ?", subscriptionID)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected > 0
+		return tx.Delete(&models.WebhookDelivery{}, "subscription_id = ? AND status = ?", subscriptionID, models.WebhookDeliveryPending).Error
+	})
+	return deleted, err
+}
+
+// AddDeliveries queues deliveries. A delivery of an event to a subscription that is already
+// queued is skipped, so an event dispatched twice by the outbox is still sent once.
+func (repo *WebhookRepository) AddDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// ClaimDue claims up to limit pending deliveries whose next attempt is due, oldest first.
+// Claimed deliveries count an attempt and are hidden from other workers for lease.
+func (repo *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().UTC()
+	var deliveries []models.WebhookDelivery
+	err := repo.DB.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, models.WebhookDeliveryPending, now, limit).Scan(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt) })
+	return deliveries, nil
+}
+
+// MarkDelivered records a successful delivery.
+func (repo *WebhookRepository) MarkDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]interface{}{"status": models.WebhookDeliveryDelivered, "last_status_code": statusCode, "last_error": ""}).Error
+}
+
+// MarkRetry records a failed attempt and when to try again.
+func (repo *WebhookRepository) MarkRetry(ctx context.Context, deliveryID uuid.UUID, statusCode int, deliveryErr string, retryAt time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]interface{}{"next_attempt_at": retryAt, "last_status_code": statusCode, "last_error": deliveryErr}).Error
+}
+
+// DeadLetter gives up on a delivery and moves it to the dead-letter table.
+func (repo *WebhookRepository) DeadLetter(ctx context.Context, delivery models.WebhookDelivery, statusCode int, deliveryErr string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.WebhookDelivery{}).
+			Where("id = ?", delivery.ID).
+			Updates(map[string]interface{}{"status": models.WebhookDeliveryDead, "last_status_code": statusCode, "last_error": deliveryErr}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&models.WebhookDeadLetter{
+			ID:             uuid.New(),
+			DeliveryID:     delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Attempts:       delivery.Attempts,
+			LastStatusCode: statusCode,
+			LastError:      deliveryErr,
+			FailedAt:       time.Now().UTC(),
+		}).Error
+	})
+}
+
+// GetDeadLetters returns up to limit dead letters, newest first. Replayed ones are included only if asked for.
+func (repo *WebhookRepository) GetDeadLetters(ctx context.Context, limit int, includeReplayed bool) ([]models.WebhookDeadLetter, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := repo.DB.WithContext(ctx).Order("failed_at DESC").Limit(limit)
+	if !includeReplayed {
+		query = query.Where("replayed_at IS NULL")
+	}
+	var deadLetters []models.WebhookDeadLetter
+	err := query.Find(&deadLetters).Error
+	return deadLetters, err
+}
+
+// GetDeadLetter returns a dead letter, or nil if it does not exist.
+func (repo *WebhookRepository) GetDeadLetter(ctx context.Context, deadLetterID uuid.UUID) (*models.WebhookDeadLetter, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var deadLetters []models.WebhookDeadLetter
+	if err := repo.DB.WithContext(ctx).Where("id = ?", deadLetterID).Limit(1).Find(&deadLetters).Error; err != nil {
+		return nil, err
+	}
+	if len(deadLetters) == 0 {
+		return nil, nil
+	}
+	return &deadLetters[0], nil
+}
+
+// ReplayDeadLetter queues the delivery of a dead letter again with fresh attempts and marks
+// the dead letter replayed. It returns false if the dead letter was already replayed.
+func (repo *WebhookRepository) ReplayDeadLetter(ctx context.Context, deadLetter *models.WebhookDeadLetter) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var replayed bool
+	err := repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		result := tx.Model(&models.WebhookDeadLetter{}).
+			Where("id = ? AND replayed_at IS NULL", deadLetter.ID).
+			Update("replayed_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		replayed = true
+
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id = ?", deadLetter.DeliveryID).
+			Updates(map[string]interface{}{"status": models.WebhookDeliveryPending, "attempts": 0, "next_attempt_at": now}).Error
+	})
+	return replayed, err
+}
@@ -15,40 +15,110 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"good-api/internal/handlers"
+	"good-api/internal/middleware"
 )
 
 // SetupRoutes defines all API routes and connects them to handlers.
-func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, userJustHandler *handlers.UserHandler, tournamentHandler *handlers.TournamentHandler, leaderboardHandler *handlers.LeaderboardHandler) {
+func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, userJustHandler *handlers.UserHandler, tournamentHandler *handlers.TournamentHandler, leaderboardHandler *handlers.LeaderboardHandler, teamHandler *handlers.TeamHandler, friendHandler *handlers.FriendHandler, countryHandler *handlers.CountryHandler, healthHandler *handlers.HealthHandler, adminHandler *handlers.AdminHandler, webhookHandler *handlers.WebhookHandler, shopHandler *handlers.ShopHandler, dailyRewardHandler *handlers.DailyRewardHandler, idempotent gin.HandlerFunc, limit middleware.Limits, adminAuth gin.HandlerFunc) {
 
 	// User routes
-	userRoutes := router.Group("/users")
+	userRoutes := router.Group("/users", limit("users", middleware.ByUserParam("id")))
 	{
-		userRoutes.POST("/", userHandler.CreateUser)      // Create a user
-		userRoutes.GET("/:id", userJustHandler.GetUser)   // Get a user by ID
-		userRoutes.GET("/", userJustHandler.GetAllUsers)  // Get all users
-		userRoutes.PUT("/:id", userHandler.UpdateUser)    // Update user
-		userRoutes.DELETE("/:id", userHandler.DeleteUser) // Delete user
+		userRoutes.POST("/", userHandler.CreateUser)                                     // Create a user
+		userRoutes.GET("/:id", userJustHandler.GetUser)                                  // Get a user by ID
+		userRoutes.POST("/batch", userJustHandler.GetUsersBatch)                         // Get several users by ID
+		userRoutes.GET("/", userJustHandler.GetAllUsers)                                 // Get all users
+		userRoutes.PUT("/:id", userHandler.UpdateUser)                                   // Update user
+		userRoutes.PATCH("/:id/username", idempotent, userHandler.ChangeUsername)        // Change username for coins
+		userRoutes.DELETE("/:id", userHandler.DeleteUser)                                // Delete user
+		userRoutes.GET("/:id/export", userHandler.ExportUser)                            // Export everything stored about a user
+		userRoutes.GET("/:id/lives", userHandler.GetLives)                               // Get lives and regeneration times
+		userRoutes.POST("/:id/lives/refill", idempotent, userHandler.RefillLives)        // Refill lives for coins
+		userRoutes.GET("/:id/inventory", shopHandler.GetInventory)                       // Get the boosters a user owns
+		userRoutes.POST("/:id/inventory/consume", idempotent, shopHandler.ConsumeItem)   // Use boosters from the inventory
+		userRoutes.GET("/:id/daily-reward", dailyRewardHandler.GetStatus)                // Get the login streak and next reward
+		userRoutes.POST("/:id/daily-reward/claim", idempotent, dailyRewardHandler.Claim) // Claim today's login reward
 
 	}
 
 	// Tournament routes
-	tournamentRoutes := router.Group("/tournaments")
+	tournamentRoutes := router.Group("/tournaments", limit("tournaments", middleware.ByUserParam("id")))
 	{
-		tournamentRoutes.GET("/", tournamentHandler.GetAllTournaments)               // Get all tournaments
-		tournamentRoutes.POST("/enter/:id", tournamentHandler.EnterTournament)       // Enter a tournament
-		tournamentRoutes.GET("/:id", tournamentHandler.GetTournament)                // Get tournament details
-		tournamentRoutes.POST("/finish/:id", tournamentHandler.FinishTournament)     // Manually finish a tournament
-		tournamentRoutes.POST("/finish-all", tournamentHandler.FinishAllTournaments) // Manually finish all tournaments
-		tournamentRoutes.PUT("/update-score/:id", tournamentHandler.UpdateScore)     // Update user's level in a tournament
+		tournamentRoutes.GET("/", tournamentHandler.GetAllTournaments)                                                                      // Get all tournaments
+		tournamentRoutes.POST("/enter/:id", idempotent, tournamentHandler.EnterTournament)                                                  // Enter a tournament
+		tournamentRoutes.GET("/:id", tournamentHandler.GetTournament)                                                                       // Get tournament details
+		tournamentRoutes.POST("/finish/:id", tournamentHandler.FinishTournament)                                                            // Manually finish a tournament
+		tournamentRoutes.POST("/finish-all", tournamentHandler.FinishAllTournaments)                                                        // Manually finish all tournaments
+		tournamentRoutes.PUT("/update-score/:id", limit("scores", middleware.ByUserParam("id")), idempotent, tournamentHandler.UpdateScore) // Update user's level in a tournament
+
+		tournamentRoutes.POST("/team/enter/:id", idempotent, tournamentHandler.EnterTeamTournament) // Enter a team into a team tournament
 	}
 
+	// Team routes
+	teamRoutes := router.Group("/teams", limit("teams", middleware.ByClientIP))
+	{
+		teamRoutes.POST("/", teamHandler.CreateTeam)           // Create a team
+		teamRoutes.GET("/:id", teamHandler.GetTeam)            // Get team details
+		teamRoutes.GET("/:id/members", teamHandler.GetMembers) // Get team members
+		teamRoutes.POST("/:id/join", teamHandler.JoinTeam)     // Join a team
+		teamRoutes.POST("/:id/leave", teamHandler.LeaveTeam)   // Leave a team
+		teamRoutes.POST("/:id/kick", teamHandler.KickMember)   // Kick a member
+		teamRoutes.PUT("/:id/role", teamHandler.SetMemberRole) // Change a member's role
+	}
+
+	// Friend routes
+	friendRoutes := router.Group("/friends", limit("friends", middleware.ByClientIP))
+	{
+		friendRoutes.POST("/request", friendHandler.SendRequest)             // Send a friend request
+		friendRoutes.POST("/accept", friendHandler.AcceptRequest)            // Accept a friend request
+		friendRoutes.POST("/remove", friendHandler.RemoveFriend)             // Remove a friend or decline a request
+		friendRoutes.POST("/block", friendHandler.BlockUser)                 // Block a user
+		friendRoutes.PUT("/contact-hash", friendHandler.SetContactHash)      // Register the user's hashed contact
+		friendRoutes.POST("/import", friendHandler.ImportContacts)           // Find friends by hashed device contacts
+		friendRoutes.GET("/:id", friendHandler.GetFriends)                   // Get a user's friends
+		friendRoutes.GET("/:id/requests", friendHandler.GetIncomingRequests) // Get pending friend requests
+	}
+
+	// Shop routes
+	shopRoutes := router.Group("/shop", limit("shop", middleware.ByUserBody("user_id")))
+	{
+		shopRoutes.GET("/items", shopHandler.GetItems)                 // List the catalog with prices
+		shopRoutes.POST("/purchase", idempotent, shopHandler.Purchase) // Buy boosters with coins
+	}
+
+	// Health routes
+	router.GET("/healthz", healthHandler.Healthz) // Liveness probe
+	router.GET("/readyz", healthHandler.Readyz)   // Readiness probe, checks Postgres, Redis and migrations
+
+	// Admin routes, for operators holding the admin token
+	adminRoutes := router.Group("/admin", adminAuth)
+	{
+		adminRoutes.GET("/config", adminHandler.GetConfig)                               // Effective configuration with secrets redacted
+		adminRoutes.POST("/leaderboard/rebuild", leaderboardHandler.RebuildLeaderboards) // Rebuild the global and country leaderboards from the database
+
+		adminRoutes.POST("/webhooks/subscriptions", webhookHandler.CreateSubscription)         // Subscribe a partner URL to event types
+		adminRoutes.GET("/webhooks/subscriptions", webhookHandler.GetSubscriptions)            // List webhook subscriptions
+		adminRoutes.DELETE("/webhooks/subscriptions/:id", webhookHandler.DeleteSubscription)   // Delete a webhook subscription
+		adminRoutes.GET("/webhooks/dead-letters", webhookHandler.GetDeadLetters)               // List failed webhook deliveries
+		adminRoutes.POST("/webhooks/dead-letters/:id/replay", webhookHandler.ReplayDeadLetter) // Replay a failed webhook delivery
+
+		adminRoutes.PUT("/shop/items/:id", shopHandler.UpdateItem) // Change a shop item's price or availability
+	}
+
+	// Country routes
+	router.GET("/countries", countryHandler.GetCountries) // List countries for the country leaderboard selector
+
 	// Leaderboard routes
-	leaderboardRoutes := router.Group("/leaderboard")
+	leaderboardRoutes := router.Group("/leaderboard", limit("leaderboard", middleware.ByUserQuery("user_id")))
 	{
 		leaderboardRoutes.GET("/global", leaderboardHandler.GetGlobalLeaderboard)   // will get users who compete in any tournament and rank them globally.
 		leaderboardRoutes.GET("/country", leaderboardHandler.GetCountryLeaderboard) // will get users who compete in any tournament and rank them according to country we choose.
+		leaderboardRoutes.GET("/global/rank", leaderboardHandler.GetGlobalRank)     // will get the user's global rank, percentile and neighbours.
+		leaderboardRoutes.GET("/country/rank", leaderboardHandler.GetCountryRank)   // will get the user's rank, percentile and neighbours in their country.
 		leaderboardRoutes.GET("/tournament", leaderboardHandler.GetTournamentLeaderboard)
 		leaderboardRoutes.GET("/tournament/rank", leaderboardHandler.GetTournamentRank)
+		leaderboardRoutes.GET("/team", leaderboardHandler.GetTeamLeaderboard)       // will get the teams of a team tournament ranked by score.
+		leaderboardRoutes.GET("/friends", leaderboardHandler.GetFriendsLeaderboard) // will get the user's friends and the user ranked by level.
 	}
 
 }
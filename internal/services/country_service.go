@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"good-api/internal/cache"
+	"good-api/internal/countries"
+	"sort"
+)
+
+// CountryInfo is a country shown in the country leaderboard selector.
+type CountryInfo struct {
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	PlayerCount int    `json:"player_count"`
+}
+
+type CountryService struct{}
+
+func NewCountryService() *CountryService {
+	return &CountryService{}
+}
+
+// GetCountries lists countries with the number of players on their leaderboard,
+// most popular first. Countries without players are only included if includeEmpty is set.
+func (s *CountryService) GetCountries(ctx context.Context, includeEmpty bool) ([]CountryInfo, error) {
+	all := countries.All()
+	codes := make([]string, 0, len(all)+1)
+	for _, c := range all {
+		codes = append(codes, c.Code)
+	}
+	codes = append(codes, countries.Unknown)
+
+	counts, err := cache.CountCountryPlayers(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CountryInfo, 0, len(codes))
+	for _, code := range codes {
+		if counts[code] == 0 && !includeEmpty {
+			continue
+		}
+		result = append(result, CountryInfo{
+			Code:        code,
+			Name:        countries.Name(code),
+			PlayerCount: counts[code],
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].PlayerCount > result[j].PlayerCount
+	})
+	return result, nil
+}
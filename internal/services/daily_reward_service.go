@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/metrics"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type DailyRewardService struct {
+	Repo     *repositories.DailyRewardRepository
+	UserRepo *repositories.UserRepository
+	Rules    config.GameConfig
+	Logger   *slog.Logger
+	// Now returns the current time; tests replace it to move between days
+	Now func() time.Time
+}
+
+func NewDailyRewardService(repo *repositories.DailyRewardRepository, userRepo *repositories.UserRepository, rules config.GameConfig, logger *slog.Logger) *DailyRewardService {
+	if repo == nil || userRepo == nil {
+		panic("DailyRewardService: Repositories must not be nil")
+	}
+	return &DailyRewardService{Repo: repo, UserRepo: userRepo, Rules: rules, Logger: logger, Now: time.Now}
+}
+
+// Claim pays the user's daily login reward for the current UTC day. Claiming yesterday's reward
+// continues the streak; a missed day starts it over. A second claim on the same day pays
+// nothing and returns the first claim.
+func (s *DailyRewardService) Claim(ctx context.Context, userID uuid.UUID) (*models.DailyReward, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+
+	now := s.Now().UTC()
+	today := models.UTCDay(now)
+	last, err := s.Repo.GetLastClaim(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil && !last.Day.Before(today) {
+		return &models.DailyReward{Claim: *last, AlreadyClaimed: true, Balance: user.Coins}, nil
+	}
+
+	streak := 1
+	if last != nil && last.Day.Equal(today.AddDate(0, 0, -1)) {
+		streak = last.Streak + 1
+	}
+	claim := &models.DailyRewardClaim{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Day:       today,
+		Streak:    streak,
+		Coins:     s.Rules.DailyReward(streak),
+		ClaimedAt: now,
+	}
+
+	balance, err := s.Repo.Claim(ctx, claim)
+	if errors.Is(err, repositories.ErrDailyRewardClaimed) {
+		// A concurrent request claimed it first
+		return s.claimedToday(ctx, userID)
+	}
+	if err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+
+	metrics.DailyRewardsClaimed.Inc()
+	s.Logger.InfoContext(ctx, "claimed daily reward", "user_id", userID, "streak", streak, "coins", claim.Coins)
+	return &models.DailyReward{Claim: *claim, Balance: balance}, nil
+}
+
+// claimedToday returns the daily reward the user already claimed today.
+func (s *DailyRewardService) claimedToday(ctx context.Context, userID uuid.UUID) (*models.DailyReward, error) {
+	last, err := s.Repo.GetLastClaim(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+	return &models.DailyReward{Claim: *last, AlreadyClaimed: true, Balance: user.Coins}, nil
+}
+
+// GetStatus returns the user's current login streak and what their next claim pays.
+func (s *DailyRewardService) GetStatus(ctx context.Context, userID uuid.UUID) (*models.DailyRewardStatus, error) {
+	if _, err := s.UserRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+	last, err := s.Repo.GetLastClaim(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := models.UTCDay(s.Now())
+	status := &models.DailyRewardStatus{NextClaimAt: today, Calendar: s.Rules.DailyRewards}
+	if last != nil {
+		switch {
+		case !last.Day.Before(today):
+			status.Streak = last.Streak
+			status.ClaimedToday = true
+			status.NextClaimAt = today.AddDate(0, 0, 1)
+		case last.Day.Equal(today.AddDate(0, 0, -1)):
+			// Still alive until today ends
+			status.Streak = last.Streak
+		}
+	}
+	status.NextReward = s.Rules.DailyReward(status.Streak + 1)
+	return status, nil
+}
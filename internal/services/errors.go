@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Kind classifies a domain error. Handlers choose the HTTP status from it.
+type Kind int
+
+const (
+	NotFound Kind = iota + 1
+	AlreadyEntered
+	EntryClosed
+	InsufficientFunds
+	Conflict
+	Invalid
+	Forbidden
+)
+
+// Error is a domain error the client can act on. Code is stable and machine readable,
+// so the game client can show a localized message; Message is the English fallback.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// AsError returns the domain error in err's chain, or nil if err is not a domain error.
+func AsError(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return nil
+}
+
+// IsKind reports whether err is a domain error of the given kind.
+func IsKind(err error, kind Kind) bool {
+	domainErr := AsError(err)
+	return domainErr != nil && domainErr.Kind == kind
+}
+
+// Errors shared by several services.
+var (
+	ErrUserNotFound          = newError(NotFound, "user_not_found", "user not found")
+	ErrTeamNotFound          = newError(NotFound, "team_not_found", "team not found")
+	ErrTournamentEntryClosed = newError(EntryClosed, "tournament_entry_closed", "tournament entry is closed")
+)
+
+// NotFoundAs turns a missing record into the given domain error. Any other error, such as a
+// timed out or cancelled query, is returned unchanged so handlers can map it to 503/504.
+func NotFoundAs(err error, missing *Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return missing
+	}
+	return err
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxFriends         = 500
+	maxContactsImport  = 1000
+	contactHashByteLen = 32 // SHA-256
+)
+
+// Errors returned by FriendService.
+var (
+	ErrFriendNotFound          = newError(NotFound, "friend_not_found", "friend not found")
+	ErrFriendRequestSelf       = newError(Invalid, "friend_request_self", "cannot send a friend request to yourself")
+	ErrFriendRequestNotAllowed = newError(Forbidden, "friend_request_not_allowed", "cannot send a friend request to this user")
+	ErrFriendRequestExists     = newError(Conflict, "friend_request_exists", "friend request already sent")
+	ErrFriendRequestNotFound   = newError(NotFound, "friend_request_not_found", "friend request not found")
+	ErrAlreadyFriends          = newError(Conflict, "already_friends", "users are already friends")
+	ErrNotFriends              = newError(NotFound, "not_friends", "users are not friends")
+	ErrFriendLimitReached      = newError(Conflict, "friend_limit_reached", "friend limit reached")
+	ErrBlockSelf               = newError(Invalid, "block_self", "cannot block yourself")
+	ErrInvalidContactHash      = newError(Invalid, "invalid_contact_hash", "contact hash must be a hex encoded SHA-256 digest")
+	ErrTooManyContacts         = newError(Invalid, "too_many_contacts", "too many contacts in one import")
+)
+
+type FriendService struct {
+	FriendRepo *repositories.FriendRepository
+	UserRepo   *repositories.UserRepository
+}
+
+func NewFriendService(friendRepo *repositories.FriendRepository, userRepo *repositories.UserRepository) *FriendService {
+	if friendRepo == nil || userRepo == nil {
+		panic("FriendService: Repositories must not be nil")
+	}
+	return &FriendService{
+		FriendRepo: friendRepo,
+		UserRepo:   userRepo,
+	}
+}
+
+// SendRequest sends a friend request from userID to friendID.
+// If friendID already sent a request to userID, the friendship is accepted right away.
+func (s *FriendService) SendRequest(ctx context.Context, userID, friendID uuid.UUID) (string, error) {
+	if userID == friendID {
+		return "", ErrFriendRequestSelf
+	}
+	if _, err := s.UserRepo.GetUserByID(ctx, userID); err != nil {
+		return "", NotFoundAs(err, ErrUserNotFound)
+	}
+	if _, err := s.UserRepo.GetUserByID(ctx, friendID); err != nil {
+		return "", NotFoundAs(err, ErrFriendNotFound)
+	}
+
+	outgoing, err := s.FriendRepo.GetFriendship(ctx, userID, friendID)
+	if err != nil {
+		return "", err
+	}
+	incoming, err := s.FriendRepo.GetFriendship(ctx, friendID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if (outgoing != nil && outgoing.Status == models.FriendshipBlocked) ||
+		(incoming != nil && incoming.Status == models.FriendshipBlocked) {
+		return "", ErrFriendRequestNotAllowed
+	}
+	if outgoing != nil {
+		if outgoing.Status == models.FriendshipAccepted {
+			return "", ErrAlreadyFriends
+		}
+		return "", ErrFriendRequestExists
+	}
+
+	if err := s.checkFriendLimit(ctx, userID); err != nil {
+		return "", err
+	}
+
+	if incoming != nil && incoming.Status == models.FriendshipPending {
+		if err := s.checkFriendLimit(ctx, friendID); err != nil {
+			return "", err
+		}
+		if err := s.FriendRepo.Accept(ctx, friendID, userID); err != nil {
+			return "", err
+		}
+		return models.FriendshipAccepted, nil
+	}
+
+	if _, err := s.FriendRepo.CreateRequest(ctx, userID, friendID); err != nil {
+		return "", err
+	}
+	return models.FriendshipPending, nil
+}
+
+// AcceptRequest accepts the pending request that requesterID sent to userID.
+func (s *FriendService) AcceptRequest(ctx context.Context, userID, requesterID uuid.UUID) error {
+	if err := s.checkFriendLimit(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.checkFriendLimit(ctx, requesterID); err != nil {
+		return err
+	}
+
+	err := s.FriendRepo.Accept(ctx, requesterID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrFriendRequestNotFound
+	}
+	return err
+}
+
+// RemoveFriend removes a friend, or declines or cancels a pending request.
+func (s *FriendService) RemoveFriend(ctx context.Context, userID, friendID uuid.UUID) error {
+	outgoing, err := s.FriendRepo.GetFriendship(ctx, userID, friendID)
+	if err != nil {
+		return err
+	}
+	incoming, err := s.FriendRepo.GetFriendship(ctx, friendID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Removing a friend must not lift a block
+	if (outgoing == nil || outgoing.Status == models.FriendshipBlocked) &&
+		(incoming == nil || incoming.Status == models.FriendshipBlocked) {
+		return ErrNotFriends
+	}
+	return s.FriendRepo.Remove(ctx, userID, friendID)
+}
+
+// BlockUser blocks another user. Any existing friendship or request between them is removed.
+func (s *FriendService) BlockUser(ctx context.Context, userID, friendID uuid.UUID) error {
+	if userID == friendID {
+		return ErrBlockSelf
+	}
+	if _, err := s.UserRepo.GetUserByID(ctx, friendID); err != nil {
+		return NotFoundAs(err, ErrUserNotFound)
+	}
+	return s.FriendRepo.Block(ctx, userID, friendID)
+}
+
+// GetFriends returns the accepted friends of a user.
+func (s *FriendService) GetFriends(ctx context.Context, userID uuid.UUID) ([]models.User, error) {
+	return s.FriendRepo.GetFriends(ctx, userID)
+}
+
+// GetIncomingRequests returns the users waiting for userID to accept their request.
+func (s *FriendService) GetIncomingRequests(ctx context.Context, userID uuid.UUID) ([]models.User, error) {
+	return s.FriendRepo.GetIncomingRequests(ctx, userID)
+}
+
+// SetContactHash registers the hashed device contact of a user so friends can find them.
+func (s *FriendService) SetContactHash(ctx context.Context, userID uuid.UUID, hash string) error {
+	hash, ok := normalizeContactHash(hash)
+	if !ok {
+		return ErrInvalidContactHash
+	}
+	if _, err := s.UserRepo.GetUserByID(ctx, userID); err != nil {
+		return NotFoundAs(err, ErrUserNotFound)
+	}
+	return s.FriendRepo.SetContactHash(ctx, userID, hash)
+}
+
+// ImportContacts matches hashed device contacts against registered users
+// and sends a friend request to each match. It returns the IDs of the matched users.
+func (s *FriendService) ImportContacts(ctx context.Context, userID uuid.UUID, hashes []string) ([]uuid.UUID, error) {
+	if len(hashes) > maxContactsImport {
+		return nil, ErrTooManyContacts
+	}
+
+	normalized := make([]string, 0, len(hashes))
+	for _, h := range hashes {
+		if h, ok := normalizeContactHash(h); ok {
+			normalized = append(normalized, h)
+		}
+	}
+	if len(normalized) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	matches, err := s.FriendRepo.FindUsersByContactHashes(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]uuid.UUID, 0, len(matches))
+	for _, friendID := range matches {
+		if friendID == userID {
+			continue
+		}
+		matched = append(matched, friendID)
+
+		// Existing friends, pending requests and blocks are skipped silently
+		_, _ = s.SendRequest(ctx, userID, friendID)
+	}
+	return matched, nil
+}
+
+func (s *FriendService) checkFriendLimit(ctx context.Context, userID uuid.UUID) error {
+	count, err := s.FriendRepo.CountFriends(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= maxFriends {
+		return ErrFriendLimitReached
+	}
+	return nil
+}
+
+// normalizeContactHash lowercases a hash and checks that it is a hex encoded SHA-256 digest.
+func normalizeContactHash(hash string) (string, bool) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	decoded, err := hex.DecodeString(hash)
+	if err != nil || len(decoded) != contactHashByteLen {
+		return "", false
+	}
+	return hash, true
+}
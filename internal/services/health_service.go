@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"good-api/internal/cache"
+	"good-api/internal/database"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Dependency check statuses
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Timeout of a single dependency check, so a hanging dependency cannot stall /readyz.
+const healthCheckTimeout = 2 * time.Second
+
+// DependencyStatus is the result of checking one dependency.
+type DependencyStatus struct {
+	Status    string                 `json:"status"`
+	LatencyMS int64                  `json:"latency_ms"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// ReadinessReport is the combined result of all dependency checks.
+type ReadinessReport struct {
+	Ready  bool                        `json:"ready"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
+
+type HealthService struct {
+	DB       *gorm.DB
+	draining atomic.Bool
+}
+
+func NewHealthService(db *gorm.DB) *HealthService {
+	return &HealthService{DB: db}
+}
+
+// SetDraining marks the service as shutting down, so /readyz fails and load balancers
+// stop sending new requests while in-flight ones finish.
+func (s *HealthService) SetDraining() {
+	s.draining.Store(true)
+}
+
+// CheckReadiness checks Postgres, Redis and the migration version concurrently.
+// The service is ready only if every dependency is up.
+func (s *HealthService) CheckReadiness(ctx context.Context) ReadinessReport {
+	checks := map[string]func(context.Context) (map[string]interface{}, error){
+		"postgres":   s.checkPostgres,
+		"redis":      checkRedis,
+		"migrations": s.checkMigrations,
+	}
+
+	report := ReadinessReport{Ready: true, Checks: make(map[string]DependencyStatus, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) (map[string]interface{}, error)) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			details, err := check(checkCtx)
+			status := DependencyStatus{Status: StatusUp, LatencyMS: time.Since(start).Milliseconds(), Details: details}
+			if err != nil {
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = status
+			if err != nil {
+				report.Ready = false
+			}
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	if s.draining.Load() {
+		report.Ready = false
+		report.Checks["server"] = DependencyStatus{Status: StatusDown, Error: "shutting down"}
+	}
+	return report
+}
+
+func (s *HealthService) checkPostgres(ctx context.Context) (map[string]interface{}, error) {
+	stats, err := database.Ping(ctx, s.DB)
+	return map[string]interface{}{
+		"open_connections": stats.OpenConnections,
+		"in_use":           stats.InUse,
+		"idle":             stats.Idle,
+		"max_open":         stats.MaxOpenConnections,
+		"wait_count":       stats.WaitCount,
+	}, err
+}
+
+func checkRedis(ctx context.Context) (map[string]interface{}, error) {
+	return nil, cache.Ping(ctx)
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) (map[string]interface{}, error) {
+	version, err := database.MigrationVersion(ctx, s.DB)
+	return map[string]interface{}{
+		"version":  version,
+		"expected": database.ExpectedMigrationVersion(),
+	}, err
+}
@@ -1,31 +1,38 @@
 package services
 
 import (
+	"context"
 	"good-api/internal/cache"
+	"good-api/internal/models"
 	"good-api/internal/repositories"
+	"log/slog"
+	"math"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type LeaderboardService struct {
 	LeaderboardRepo *repositories.LeaderboardRepository
+	UserRepo        *repositories.UserRepository
+	Logger          *slog.Logger
 }
 
-func NewLeaderboardService(repo *repositories.LeaderboardRepository) *LeaderboardService {
-	return &LeaderboardService{LeaderboardRepo: repo}
+func NewLeaderboardService(repo *repositories.LeaderboardRepository, userRepo *repositories.UserRepository, logger *slog.Logger) *LeaderboardService {
+	return &LeaderboardService{LeaderboardRepo: repo, UserRepo: userRepo, Logger: logger}
 }
 
 // GetTournamentLeaderboard fetches the leaderboard of a specific tournament.
-func (s *LeaderboardService) GetTournamentLeaderboard(tournamentID string, limit int) ([]string, error) {
+func (s *LeaderboardService) GetTournamentLeaderboard(ctx context.Context, tournamentID string, limit int) ([]string, error) {
 	tID, err := uuid.Parse(tournamentID)
 	if err != nil {
 		return nil, err
 	}
-	return cache.GetTournamentLeaderboard(tID, limit)
+	return cache.GetTournamentLeaderboard(ctx, tID, limit)
 }
 
 // GetTournamentRank fetches the rank of a user in a tournament.
-func (s *LeaderboardService) GetTournamentRank(userID string, tournamentID string) (int, error) {
+func (s *LeaderboardService) GetTournamentRank(ctx context.Context, userID string, tournamentID string) (int, error) {
 	uID, err := uuid.Parse(userID)
 	if err != nil {
 		return 0, err
@@ -34,5 +41,309 @@ func (s *LeaderboardService) GetTournamentRank(userID string, tournamentID strin
 	if err != nil {
 		return 0, err
 	}
-	return s.LeaderboardRepo.GetTournamentRank(uID, tID)
+	return s.LeaderboardRepo.GetTournamentRank(ctx, uID, tID)
+}
+
+// GetTeamLeaderboard fetches the team standings of a team tournament.
+func (s *LeaderboardService) GetTeamLeaderboard(ctx context.Context, tournamentID string, limit int) ([]cache.LeaderboardEntry, error) {
+	tID, err := uuid.Parse(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	return cache.GetTeamLeaderboard(ctx, tID, limit)
+}
+
+// GetFriendsLeaderboard ranks a user's friends and the user themselves by level.
+func (s *LeaderboardService) GetFriendsLeaderboard(ctx context.Context, userID string) ([]models.LeaderboardRow, error) {
+	uID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.LeaderboardRepo.GetFriendsLeaderboard(ctx, uID)
+}
+
+// Upper bound for leaderboard pages served from Redis.
+const maxLeaderboardLimit = 1000
+
+// GetGlobalLeaderboard fetches the top users globally. If userID is given,
+// the caller's own global rank is included in the page.
+func (s *LeaderboardService) GetGlobalLeaderboard(ctx context.Context, userID string, limit int) (*models.LeaderboardPage, error) {
+	entries, err := cache.GetGlobalLeaderboard(ctx, clampLeaderboardLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildLeaderboardPage(ctx, entries, userID, cache.GetGlobalRank)
+}
+
+// GetCountryLeaderboard fetches the top users of a country. If userID is given and
+// the user plays for that country, the caller's own country rank is included in the page.
+func (s *LeaderboardService) GetCountryLeaderboard(ctx context.Context, country string, userID string, limit int) (*models.LeaderboardPage, error) {
+	entries, err := cache.GetCountryLeaderboard(ctx, country, clampLeaderboardLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildLeaderboardPage(ctx, entries, userID, func(ctx context.Context, uID uuid.UUID) (int, int, bool, error) {
+		return cache.GetCountryRank(ctx, uID, country)
+	})
+}
+
+// Instances journal leaderboard changes with their own clocks, so replays look back this much further.
+const leaderboardClockSkew = time.Minute
+
+// RebuildLeaderboards recreates the global and country leaderboards from Postgres.
+// Users whose level or country changed while it ran are applied again afterwards, as their
+// changes were written to the sets that were replaced. It returns the number of users that were ranked.
+func (s *LeaderboardService) RebuildLeaderboards(ctx context.Context) (int, error) {
+	start := time.Now().Add(-leaderboardClockSkew)
+	users, err := s.LeaderboardRepo.GetCompetingUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ranked := make([]cache.RankedUser, 0, len(users))
+	rebuiltCountry := make(map[uuid.UUID]string, len(users))
+	for _, user := range users {
+		ranked = append(ranked, cache.RankedUser{UserID: user.ID, Country: user.Country, Level: user.Level})
+		rebuiltCountry[user.ID] = user.Country
+	}
+
+	if err := cache.ReplaceGlobalLeaderboards(ctx, ranked); err != nil {
+		return 0, err
+	}
+
+	changed, err := cache.GetGlobalLeaderboardChanges(ctx, start)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.reapplyLeaderboardChanges(ctx, changed, rebuiltCountry); err != nil {
+		return 0, err
+	}
+	return len(ranked), nil
+}
+
+// reapplyLeaderboardChanges places the given users on the global leaderboards as Postgres
+// has them now, replacing the place the rebuild gave them in rebuiltCountry.
+func (s *LeaderboardService) reapplyLeaderboardChanges(ctx context.Context, userIDs []uuid.UUID, rebuiltCountry map[uuid.UUID]string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	users, err := s.LeaderboardRepo.GetCompetingUsersByID(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	current := make(map[uuid.UUID]models.User, len(users))
+	for _, user := range users {
+		current[user.ID] = user
+	}
+
+	for _, userID := range userIDs {
+		user, ranked := current[userID]
+		if country, rebuilt := rebuiltCountry[userID]; rebuilt && (!ranked || user.Country != country) {
+			if err := cache.RemoveUserFromGlobalLeaderboards(ctx, userID, country); err != nil {
+				return err
+			}
+		}
+		if ranked {
+			if err := cache.AddUserToGlobalLeaderboards(ctx, user.ID, user.Country, user.Level); err != nil {
+				return err
+			}
+		}
+	}
+	s.Logger.DebugContext(ctx, "reapplied leaderboard changes made during the rebuild", "users", len(userIDs))
+	return nil
+}
+
+// EnsureLeaderboards rebuilds the global and country leaderboards if Redis does not have them yet,
+// for example after a Redis restart.
+func (s *LeaderboardService) EnsureLeaderboards(ctx context.Context) error {
+	exists, err := cache.GlobalLeaderboardExists(ctx)
+	if err != nil || exists {
+		return err
+	}
+
+	count, err := s.RebuildLeaderboards(ctx)
+	if err != nil {
+		return err
+	}
+	s.Logger.InfoContext(ctx, "global leaderboards rebuilt from database", "users_ranked", count)
+	return nil
+}
+
+// buildLeaderboardPage turns Redis entries into ranked rows with user details,
+// and adds the caller's own row using rankFn.
+func (s *LeaderboardService) buildLeaderboardPage(ctx context.Context, entries []cache.LeaderboardEntry, userID string, rankFn func(context.Context, uuid.UUID) (int, int, bool, error)) (*models.LeaderboardPage, error) {
+	page := &models.LeaderboardPage{Leaderboard: make([]models.LeaderboardRow, 0, len(entries))}
+
+	var me *models.LeaderboardRow
+	if userID != "" {
+		uID, err := uuid.Parse(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		rank, level, found, err := rankFn(ctx, uID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			me = &models.LeaderboardRow{Rank: rank, UserID: uID, Level: level}
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(entries)+1)
+	for _, entry := range entries {
+		if id, err := uuid.Parse(entry.Member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if me != nil {
+		ids = append(ids, me.UserID)
+	}
+
+	users, err := s.UserRepo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, entry := range entries {
+		id, err := uuid.Parse(entry.Member)
+		if err != nil {
+			continue
+		}
+		user, ok := users[id]
+		if !ok {
+			continue
+		}
+		page.Leaderboard = append(page.Leaderboard, models.LeaderboardRow{
+			Rank:     i + 1,
+			UserID:   id,
+			Username: user.Username,
+			Level:    int(entry.Score),
+			Country:  user.Country,
+		})
+	}
+
+	if me != nil {
+		if user, ok := users[me.UserID]; ok {
+			me.Username = user.Username
+			me.Country = user.Country
+			page.Me = me
+		}
+	}
+	return page, nil
+}
+
+func clampLeaderboardLimit(limit int) int {
+	if limit <= 0 || limit > maxLeaderboardLimit {
+		return maxLeaderboardLimit
+	}
+	return limit
+}
+
+// Upper bound for the neighbours returned on each side of a user's rank.
+const maxRankNeighbours = 10
+
+// ErrNotRanked is returned when a user is not on the requested leaderboard.
+var ErrNotRanked = newError(NotFound, "not_ranked", "user is not ranked on this leaderboard")
+
+// GetGlobalRank fetches a user's global rank and percentile with the users ranked around them.
+func (s *LeaderboardService) GetGlobalRank(ctx context.Context, userID string, neighbours int) (*models.RankInfo, error) {
+	uID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := cache.GetGlobalRankWindow(ctx, uID, clampNeighbours(neighbours))
+	if err != nil {
+		return nil, err
+	}
+	return s.buildRankInfo(ctx, uID, window)
+}
+
+// GetCountryRank fetches a user's rank and percentile in their own country with the users ranked around them.
+func (s *LeaderboardService) GetCountryRank(ctx context.Context, userID string, neighbours int) (*models.RankInfo, error) {
+	uID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	country, err := s.LeaderboardRepo.GetUserCountry(ctx, uID)
+	if err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+
+	window, err := cache.GetCountryRankWindow(ctx, uID, country, clampNeighbours(neighbours))
+	if err != nil {
+		return nil, err
+	}
+	return s.buildRankInfo(ctx, uID, window)
+}
+
+// buildRankInfo adds user details to a rank window read from Redis.
+func (s *LeaderboardService) buildRankInfo(ctx context.Context, userID uuid.UUID, window *cache.RankWindow) (*models.RankInfo, error) {
+	if window == nil {
+		return nil, ErrNotRanked
+	}
+
+	ids := []uuid.UUID{userID}
+	for _, entry := range append(append([]cache.LeaderboardEntry{}, window.Above...), window.Below...) {
+		if id, err := uuid.Parse(entry.Member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	users, err := s.UserRepo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	toRows := func(entries []cache.LeaderboardEntry, firstRank int) []models.LeaderboardRow {
+		rows := make([]models.LeaderboardRow, 0, len(entries))
+		for i, entry := range entries {
+			id, err := uuid.Parse(entry.Member)
+			if err != nil {
+				continue
+			}
+			user := users[id]
+			rows = append(rows, models.LeaderboardRow{
+				Rank:     firstRank + i,
+				UserID:   id,
+				Username: user.Username,
+				Level:    int(entry.Score),
+				Country:  user.Country,
+			})
+		}
+		return rows
+	}
+
+	me := users[userID]
+	info := &models.RankInfo{
+		Rank:  window.Rank,
+		Total: window.Total,
+		Me: models.LeaderboardRow{
+			Rank:     window.Rank,
+			UserID:   userID,
+			Username: me.Username,
+			Level:    window.Score,
+			Country:  me.Country,
+		},
+		Above: toRows(window.Above, window.Rank-len(window.Above)),
+		Below: toRows(window.Below, window.Rank+1),
+	}
+	if window.Total > 0 {
+		info.Percentile = math.Round(float64(window.Total-window.Rank)/float64(window.Total)*10000) / 100
+	}
+	return info, nil
+}
+
+func clampNeighbours(n int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > maxRankNeighbours {
+		return maxRankNeighbours
+	}
+	return n
 }
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"good-api/internal/metrics"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Errors returned by ShopService.
+var (
+	ErrShopItemNotFound    = newError(NotFound, "shop_item_not_found", "shop item not found")
+	ErrShopItemUnavailable = newError(Conflict, "shop_item_unavailable", "shop item is not for sale")
+	ErrPurchaseCoins       = newError(InsufficientFunds, "insufficient_coins", "not enough coins for this purchase")
+	ErrNotEnoughItems      = newError(Conflict, "not_enough_items", "not enough of this item in the inventory")
+)
+
+type ShopService struct {
+	repo     *repositories.ShopRepository
+	userRepo *repositories.UserRepository
+	logger   *slog.Logger
+}
+
+func NewShopService(repo *repositories.ShopRepository, userRepo *repositories.UserRepository, logger *slog.Logger) *ShopService {
+	if repo == nil || userRepo == nil {
+		panic("ShopService: Repositories must not be nil")
+	}
+	return &ShopService{repo: repo, userRepo: userRepo, logger: logger}
+}
+
+// GetItems returns the shop catalog, including the items that are not for sale right now.
+func (s *ShopService) GetItems(ctx context.Context) ([]models.ShopItem, error) {
+	return s.repo.GetItems(ctx)
+}
+
+// ShopItemUpdate holds the shop item fields admins can change. Nil fields are left as they are.
+type ShopItemUpdate struct {
+	Price     *int
+	Available *bool
+}
+
+// UpdateItem changes a shop item's price or availability. Purchases already made keep their price.
+func (s *ShopService) UpdateItem(ctx context.Context, itemID string, update ShopItemUpdate) (*models.ShopItem, error) {
+	item, err := s.repo.UpdateItem(ctx, itemID, update.Price, update.Available)
+	if errors.Is(err, repositories.ErrItemNotFound) {
+		return nil, ErrShopItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "updated shop item", "item_id", item.ID, "price", item.Price, "available", item.Available)
+	return item, nil
+}
+
+// Purchase buys quantity of an item for the user, paying with their coins.
+func (s *ShopService) Purchase(ctx context.Context, userID uuid.UUID, itemID string, quantity int) (*models.Purchase, error) {
+	purchase, err := s.repo.Purchase(ctx, userID, itemID, quantity)
+	switch {
+	case errors.Is(err, repositories.ErrItemNotFound):
+		return nil, ErrShopItemNotFound
+	case errors.Is(err, repositories.ErrItemUnavailable):
+		return nil, ErrShopItemUnavailable
+	case errors.Is(err, repositories.ErrNotEnoughCoins):
+		return nil, ErrPurchaseCoins
+	case err != nil:
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+
+	metrics.ShopPurchases.WithLabelValues(itemID).Add(float64(quantity))
+	metrics.ShopCoinsSpent.Add(float64(purchase.Paid))
+	s.logger.InfoContext(ctx, "shop purchase", "user_id", userID, "item_id", itemID, "quantity", quantity, "paid", purchase.Paid)
+	return purchase, nil
+}
+
+// GetInventory returns the items a user owns.
+func (s *ShopService) GetInventory(ctx context.Context, userID uuid.UUID) ([]models.InventoryItem, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+	return s.repo.GetInventory(ctx, userID)
+}
+
+// ConsumeItem uses quantity of an item from the user's inventory, such as a booster played in a level.
+func (s *ShopService) ConsumeItem(ctx context.Context, userID uuid.UUID, itemID string, quantity int) (*models.InventoryItem, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+	item, err := s.repo.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrShopItemNotFound
+	}
+
+	inventory, err := s.repo.ConsumeItem(ctx, userID, itemID, quantity)
+	if errors.Is(err, repositories.ErrNotEnoughItems) {
+		return nil, ErrNotEnoughItems
+	}
+	return inventory, err
+}
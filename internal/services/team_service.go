@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	teamNameMinLength = 3
+	teamNameMaxLength = 24
+	teamMaxMembers    = 50
+)
+
+// Errors returned by TeamService.
+var (
+	ErrTeamNameLength       = newError(Invalid, "team_name_length", "team name must be between 3 and 24 characters")
+	ErrTeamNameTaken        = newError(Conflict, "team_name_taken", "team name is already taken")
+	ErrAlreadyInTeam        = newError(Conflict, "already_in_team", "user is already in a team")
+	ErrNotTeamMember        = newError(NotFound, "not_team_member", "user is not a member of this team")
+	ErrKickSelf             = newError(Invalid, "team_kick_self", "use leave to remove yourself from a team")
+	ErrKickNotAllowed       = newError(Forbidden, "team_kick_not_allowed", "not allowed to kick this member")
+	ErrInvalidRole          = newError(Invalid, "team_invalid_role", "invalid role")
+	ErrRoleChangeNotAllowed = newError(Forbidden, "team_role_change_not_allowed", "only the team leader can change roles")
+	ErrLeaderRoleChange     = newError(Forbidden, "team_leader_role_change", "leader cannot change their own role")
+)
+
+// roleRank orders team roles so permission checks can compare them.
+var roleRank = map[string]int{
+	models.TeamRoleLeader:  3,
+	models.TeamRoleOfficer: 2,
+	models.TeamRoleMember:  1,
+}
+
+type TeamService struct {
+	TeamRepo *repositories.TeamRepository
+	UserRepo *repositories.UserRepository
+}
+
+func NewTeamService(teamRepo *repositories.TeamRepository, userRepo *repositories.UserRepository) *TeamService {
+	if teamRepo == nil || userRepo == nil {
+		panic("TeamService: Repositories must not be nil")
+	}
+	return &TeamService{
+		TeamRepo: teamRepo,
+		UserRepo: userRepo,
+	}
+}
+
+// CreateTeam creates a new team with the given user as its leader.
+func (s *TeamService) CreateTeam(ctx context.Context, ownerID uuid.UUID, name, description string) (*models.Team, error) {
+	name = strings.TrimSpace(name)
+	if len(name) < teamNameMinLength || len(name) > teamNameMaxLength {
+		return nil, ErrTeamNameLength
+	}
+
+	if _, err := s.UserRepo.GetUserByID(ctx, ownerID); err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+
+	membership, err := s.TeamRepo.GetMembership(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if membership != nil {
+		return nil, ErrAlreadyInTeam
+	}
+
+	existing, err := s.TeamRepo.GetTeamByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrTeamNameTaken
+	}
+
+	team := &models.Team{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: description,
+		OwnerID:     ownerID,
+		MaxMembers:  teamMaxMembers,
+	}
+	return s.TeamRepo.CreateTeam(ctx, team)
+}
+
+// GetTeam returns a team by ID.
+func (s *TeamService) GetTeam(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
+	team, err := s.TeamRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+	return team, nil
+}
+
+// GetMembers returns the members of a team.
+func (s *TeamService) GetMembers(ctx context.Context, teamID uuid.UUID) ([]models.TeamMember, error) {
+	if _, err := s.GetTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return s.TeamRepo.GetMembers(ctx, teamID)
+}
+
+// JoinTeam adds a user to a team as a regular member.
+func (s *TeamService) JoinTeam(ctx context.Context, teamID, userID uuid.UUID) (*models.TeamMember, error) {
+	if _, err := s.GetTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.UserRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+
+	membership, err := s.TeamRepo.GetMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if membership != nil {
+		return nil, ErrAlreadyInTeam
+	}
+
+	return s.TeamRepo.AddMember(ctx, teamID, userID)
+}
+
+// LeaveTeam removes a user from their team.
+// A leaving leader hands the team over to the most senior officer, or the oldest member
+// if there are no officers. The team is deleted when its last member leaves.
+func (s *TeamService) LeaveTeam(ctx context.Context, teamID, userID uuid.UUID) error {
+	member, err := s.getMember(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	return leaveTeam(ctx, s.TeamRepo, member)
+}
+
+// leaveTeam removes a membership, handing the team over first if the member leads it.
+// Deleting a user's account leaves their team the same way.
+func leaveTeam(ctx context.Context, teamRepo *repositories.TeamRepository, member *models.TeamMember) error {
+	if member.Role == models.TeamRoleLeader {
+		members, err := teamRepo.GetMembers(ctx, member.TeamID)
+		if err != nil {
+			return err
+		}
+
+		successor := pickSuccessor(members, member.UserID)
+		if successor == nil {
+			return teamRepo.DeleteTeam(ctx, member.TeamID)
+		}
+
+		if err := teamRepo.TransferLeadership(ctx, member.TeamID, member.UserID, successor.UserID); err != nil {
+			return err
+		}
+	}
+
+	return teamRepo.RemoveMember(ctx, member.TeamID, member.UserID)
+}
+
+// KickMember removes another member from a team.
+// Only members with a higher role than the target can kick them.
+func (s *TeamService) KickMember(ctx context.Context, teamID, actorID, targetID uuid.UUID) error {
+	if actorID == targetID {
+		return ErrKickSelf
+	}
+
+	actor, err := s.getMember(ctx, teamID, actorID)
+	if err != nil {
+		return err
+	}
+	target, err := s.getMember(ctx, teamID, targetID)
+	if err != nil {
+		return err
+	}
+
+	if actor.Role == models.TeamRoleMember || roleRank[actor.Role] <= roleRank[target.Role] {
+		return ErrKickNotAllowed
+	}
+
+	return s.TeamRepo.RemoveMember(ctx, teamID, targetID)
+}
+
+// SetMemberRole changes the role of a member. Only the leader can change roles.
+// Promoting someone to leader transfers leadership and demotes the current leader to officer.
+func (s *TeamService) SetMemberRole(ctx context.Context, teamID, actorID, targetID uuid.UUID, role string) error {
+	if _, ok := roleRank[role]; !ok {
+		return ErrInvalidRole
+	}
+
+	actor, err := s.getMember(ctx, teamID, actorID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != models.TeamRoleLeader {
+		return ErrRoleChangeNotAllowed
+	}
+	if actorID == targetID {
+		return ErrLeaderRoleChange
+	}
+
+	if _, err := s.getMember(ctx, teamID, targetID); err != nil {
+		return err
+	}
+
+	if role == models.TeamRoleLeader {
+		return s.TeamRepo.TransferLeadership(ctx, teamID, actorID, targetID)
+	}
+	return s.TeamRepo.UpdateMemberRole(ctx, teamID, targetID, role)
+}
+
+// getMember returns the membership of a user in the given team.
+func (s *TeamService) getMember(ctx context.Context, teamID, userID uuid.UUID) (*models.TeamMember, error) {
+	member, err := s.TeamRepo.GetMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.TeamID != teamID {
+		return nil, ErrNotTeamMember
+	}
+	return member, nil
+}
+
+// pickSuccessor chooses the next leader from members ordered by join date.
+func pickSuccessor(members []models.TeamMember, leaderID uuid.UUID) *models.TeamMember {
+	var oldest *models.TeamMember
+	for i := range members {
+		if members[i].UserID == leaderID {
+			continue
+		}
+		if members[i].Role == models.TeamRoleOfficer {
+			return &members[i]
+		}
+		if oldest == nil {
+			oldest = &members[i]
+		}
+	}
+	return oldest
+}
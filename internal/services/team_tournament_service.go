@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"good-api/internal/cache"
+	"good-api/internal/metrics"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors returned when entering a team tournament.
+var (
+	ErrTeamEntryNotAllowed = newError(Forbidden, "team_entry_not_allowed", "only the team leader or an officer can enter a tournament")
+	ErrTeamAlreadyEntered  = newError(AlreadyEntered, "team_already_entered", "team is already in a tournament")
+)
+
+// EnterTeamTournament registers a team in today's team tournament.
+// Only the leader or an officer can enter the team.
+func (service *TournamentService) EnterTeamTournament(ctx context.Context, teamID, actorID uuid.UUID) (*models.Tournament, error) {
+	now := time.Now().UTC()
+	if now.After(service.Rules.EntryCutoff(now)) {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectEntryClosed, ErrTournamentEntryClosed)
+	}
+
+	team, err := service.TeamRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectInternal, err)
+	}
+	if team == nil {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectNotFound, ErrTeamNotFound)
+	}
+
+	member, err := service.TeamRepo.GetMembership(ctx, actorID)
+	if err != nil {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectInternal, err)
+	}
+	if member == nil || member.TeamID != teamID || member.Role == models.TeamRoleMember {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectNotAllowed, ErrTeamEntryNotAllowed)
+	}
+
+	entry, err := service.TeamRepo.GetActiveEntry(ctx, teamID)
+	if err != nil {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectInternal, err)
+	}
+	if entry != nil {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectAlreadyEntered, ErrTeamAlreadyEntered)
+	}
+
+	// Find an active team tournament with space
+	tournament, err := service.TeamRepo.GetActiveTeamTournament(ctx)
+	if err != nil {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectInternal, err)
+	}
+	if tournament == nil {
+		tournament, err = service.TeamRepo.NewTeamTournament(ctx, service.Rules.TeamTournamentMaxTeams)
+		if err != nil {
+			return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectInternal, err)
+		}
+	}
+
+	err = service.TeamRepo.AddTeamEntry(ctx, tournament.ID, teamID)
+	if errors.Is(err, repositories.ErrTeamTournamentFull) {
+		// Another team took the last seat, so the team starts a new tournament
+		tournament, err = service.TeamRepo.NewTeamTournament(ctx, service.Rules.TeamTournamentMaxTeams)
+		if err != nil {
+			return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectInternal, err)
+		}
+		err = service.TeamRepo.AddTeamEntry(ctx, tournament.ID, teamID)
+	}
+	if errors.Is(err, repositories.ErrTeamEntryExists) {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectAlreadyEntered, ErrTeamAlreadyEntered)
+	}
+	if err != nil {
+		return nil, rejectEntry(models.TournamentModeTeam, metrics.RejectInternal, err)
+	}
+	metrics.TournamentEntries.WithLabelValues(models.TournamentModeTeam).Inc()
+	service.RefreshTournamentGauges(ctx)
+
+	if err := cache.AddTeamToLeaderboard(ctx, tournament.ID, teamID); err != nil {
+		service.Logger.ErrorContext(ctx, "failed to add team to leaderboard", "team_id", teamID, "error", err)
+	}
+
+	return tournament, nil
+}
+
+// splitTeamReward divides a team reward between members proportionally to their contribution.
+// Members without contribution get nothing. Coins lost to rounding go to the top contributors.
+func splitTeamReward(reward int, contributions []cache.LeaderboardEntry) map[string]int {
+	shares := make(map[string]int)
+
+	total := 0.0
+	var contributors []cache.LeaderboardEntry
+	for _, c := range contributions {
+		if c.Score > 0 {
+			total += c.Score
+			contributors = append(contributors, c)
+		}
+	}
+	if reward <= 0 || total == 0 {
+		return shares
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].Score > contributors[j].Score
+	})
+
+	distributed := 0
+	for _, c := range contributors {
+		share := int(float64(reward) * c.Score / total)
+		shares[c.Member] = share
+		distributed += share
+	}
+
+	for i := 0; distributed < reward; i = (i + 1) % len(contributors) {
+		shares[contributors[i].Member]++
+		distributed++
+	}
+
+	return shares
+}
+
+// payOutTeamTournament persists the team scores of a finished team tournament and pays out
+// each team's reward to its contributing members, starting after the last checkpoint.
+// If ctx is cancelled, it stops after the current team and keeps the checkpoint for the next run.
+func (service *TournamentService) payOutTeamTournament(ctx context.Context, tournamentID uuid.UUID) error {
+	leaderboard, err := cache.GetTeamLeaderboard(ctx, tournamentID, 0)
+	if err != nil {
+		return err
+	}
+
+	paid, err := cache.GetPayoutCheckpoint(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	var teamIDs []uuid.UUID
+	for rank, entry := range leaderboard {
+		teamID, err := uuid.Parse(entry.Member)
+		if err != nil {
+			continue
+		}
+		teamIDs = append(teamIDs, teamID)
+		if rank < paid {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			service.Logger.WarnContext(ctx, "team tournament payout interrupted", "tournament_id", tournamentID, "paid_ranks", rank)
+			return err
+		}
+
+		// Errors keep the checkpoint, so the next run retries the team; members already paid are skipped
+		if err := service.TeamRepo.UpdateEntryScore(ctx, tournamentID, teamID, int(entry.Score)); err != nil {
+			service.Logger.ErrorContext(ctx, "failed to save team score", "team_id", teamID, "error", err)
+			return err
+		}
+
+		contributions, err := cache.GetTeamContributions(ctx, tournamentID, teamID)
+		if err != nil {
+			service.Logger.ErrorContext(ctx, "failed to fetch team contributions", "team_id", teamID, "error", err)
+			return err
+		}
+
+		for userIDStr, share := range splitTeamReward(service.Rules.TeamReward(rank+1), contributions) {
+			userID, err := uuid.Parse(userIDStr)
+			if err != nil {
+				continue
+			}
+			err = service.TournamentRepo.PayReward(ctx, models.RewardPaidEvent{
+				TournamentID: tournamentID,
+				UserID:       userID,
+				TeamID:       &teamID,
+				Rank:         rank + 1,
+				Coins:        share,
+			}, false)
+			if errors.Is(err, repositories.ErrRewardAlreadyPaid) || errors.Is(err, repositories.ErrRewardUserNotFound) {
+				continue
+			}
+			if err != nil {
+				service.Logger.ErrorContext(ctx, "failed to update coins", "user_id", userID, "error", err)
+				return err
+			}
+			metrics.CoinsPaidOut.WithLabelValues(models.TournamentModeTeam).Add(float64(share))
+		}
+
+		if err := cache.SetPayoutCheckpoint(ctx, tournamentID, rank+1); err != nil {
+			return err
+		}
+	}
+
+	if err := cache.DeleteTeamLeaderboard(ctx, tournamentID, teamIDs); err != nil {
+		service.Logger.ErrorContext(ctx, "failed to delete team leaderboard from Redis", "tournament_id", tournamentID, "error", err)
+	}
+	if err := cache.DeletePayoutCheckpoint(ctx, tournamentID); err != nil {
+		service.Logger.ErrorContext(ctx, "failed to delete payout checkpoint", "tournament_id", tournamentID, "error", err)
+	}
+
+	service.Logger.InfoContext(ctx, "team tournament finished and rewards processed", "tournament_id", tournamentID)
+	return nil
+}
@@ -1,37 +1,54 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"good-api/internal/cache"
+	"good-api/internal/config"
+	"good-api/internal/metrics"
 	"good-api/internal/models"
 	"good-api/internal/repositories"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Errors returned when entering a tournament or updating a score.
+var (
+	ErrLevelTooLow         = newError(Forbidden, "tournament_level_too_low", "user level is too low to enter a tournament")
+	ErrInsufficientCoins   = newError(InsufficientFunds, "insufficient_coins", "not enough coins to pay the entry fee")
+	ErrAlreadyInTournament = newError(AlreadyEntered, "tournament_already_entered", "user is already in a tournament")
+	ErrNotInTournament     = newError(NotFound, "not_in_tournament", "user is not in a tournament")
 )
 
 type TournamentService struct {
 	TournamentRepo *repositories.TournamentRepository
 	UserRepo       *repositories.UserRepository
+	TeamRepo       *repositories.TeamRepository
+	Rules          config.GameConfig
+	Logger         *slog.Logger
 }
 
-func NewTournamentService(tournamentRepo *repositories.TournamentRepository, userRepo *repositories.UserRepository) *TournamentService {
-	if tournamentRepo == nil || userRepo == nil {
+func NewTournamentService(tournamentRepo *repositories.TournamentRepository, userRepo *repositories.UserRepository, teamRepo *repositories.TeamRepository, rules config.GameConfig, logger *slog.Logger) *TournamentService {
+	if tournamentRepo == nil || userRepo == nil || teamRepo == nil {
 		panic("TournamentService: Repositories must not be nil")
 	}
 	return &TournamentService{
 		TournamentRepo: tournamentRepo,
 		UserRepo:       userRepo,
+		TeamRepo:       teamRepo,
+		Rules:          rules,
+		Logger:         logger,
 	}
 }
 
 // EnterTournament handles adding a user to a tournament.
-func (service *TournamentService) EnterTournament(userID uuid.UUID) (*models.Tournament, error) {
+func (service *TournamentService) EnterTournament(ctx context.Context, userID uuid.UUID) (*models.Tournament, error) {
 	now := time.Now().UTC()
-	cutOffTime := time.Date(now.Year(), now.Month(), now.Day(), 19, 0, 0, 0, time.UTC)
-	if now.After(cutOffTime) {
-		return nil, errors.New("tournament entry is closed mate")
+	if now.After(service.Rules.EntryCutoff(now)) {
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectEntryClosed, ErrTournamentEntryClosed)
 	}
 
 	// Check if the UserRepo is initialized
@@ -39,14 +56,17 @@ func (service *TournamentService) EnterTournament(userID uuid.UUID) (*models.Tou
 		return nil, errors.New("userrepo is not initialized")
 	}
 	// Fetch the user safely
-	user, err := service.UserRepo.GetUserByID(userID)
-	if err != nil || user == nil {
-		return nil, errors.New("user not found")
+	user, err := service.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectNotFound, NotFoundAs(err, ErrUserNotFound))
 	}
 
 	// Check if user meets entry requirements
-	if user.Level < 10 || user.Coins < 500 {
-		return nil, errors.New("user does not meet entry requirements")
+	if user.Level < service.Rules.EntryMinLevel {
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectRequirementsNotMet, ErrLevelTooLow)
+	}
+	if user.Coins < service.Rules.EntryFee {
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectRequirementsNotMet, ErrInsufficientCoins)
 	}
 
 	// Ensure TournamentRepo is not nill
@@ -55,142 +75,293 @@ func (service *TournamentService) EnterTournament(userID uuid.UUID) (*models.Tou
 	}
 
 	// Check if user is already in a tournament
-	if _, err := service.TournamentRepo.GetUserTournament(userID); err != nil {
-		return nil, errors.New("user is already in a tournament")
+	current, err := service.TournamentRepo.GetUserTournament(ctx, userID)
+	if err != nil {
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectInternal, err)
+	}
+	if current != nil {
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectAlreadyEntered, ErrAlreadyInTournament)
 	}
 
 	// Find an active tournament with space
-	tournament, err := service.TournamentRepo.GetActiveTournament()
+	tournament, err := service.TournamentRepo.GetActiveTournament(ctx)
 	if err != nil || tournament == nil || tournament.UserCount >= tournament.MaxUsers {
-		tournament, err = service.TournamentRepo.NewTournament()
+		tournament, err = service.TournamentRepo.NewTournament(ctx, service.Rules.TournamentSize)
 		if err != nil {
-			return nil, err
+			return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectInternal, err)
 		}
 	}
 
-	// Deduct coins before entering tournament
-	user.Coins -= 500
-	_, err = service.UserRepo.UpdateUser(user)
-	if err != nil {
-		return nil, err
+	// Add user to tournament, paying the entry fee
+	err = service.TournamentRepo.AddParticipant(ctx, tournament.ID, userID, service.Rules.EntryFee)
+	if errors.Is(err, repositories.ErrNotEnoughCoins) {
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectRequirementsNotMet, ErrInsufficientCoins)
 	}
-
-	// Add user to tournament
-	err = service.TournamentRepo.AddParticipant(tournament.ID, userID)
 	if err != nil {
-		return nil, err
+		return nil, rejectEntry(models.TournamentModeSolo, metrics.RejectInternal, err)
 	}
 
-	fmt.Printf("User %s entered tournament %s. Adding to Redis...\n", userID, tournament.ID)
+	service.Logger.InfoContext(ctx, "user entered tournament", "user_id", userID, "tournament_id", tournament.ID)
+	metrics.TournamentEntries.WithLabelValues(models.TournamentModeSolo).Inc()
+	service.RefreshTournamentGauges(ctx)
 
-	cache.AddUserToLeaderboard(tournament.ID, user.ID, user.Level)
+	cache.AddUserToLeaderboard(ctx, tournament.ID, user.ID, user.Level)
+
+	// Competing users are ranked on the global and country leaderboards
+	if err := cache.AddUserToGlobalLeaderboards(ctx, user.ID, user.Country, user.Level); err != nil {
+		service.Logger.ErrorContext(ctx, "failed to add user to global leaderboards", "user_id", user.ID, "error", err)
+	}
 
 	return tournament, nil
 }
 
-func (service *TournamentService) GetTournamentByID(tournamentID uuid.UUID) (*models.Tournament, error) {
-	return service.TournamentRepo.GetTournamentByID(tournamentID)
+func (service *TournamentService) GetTournamentByID(ctx context.Context, tournamentID uuid.UUID) (*models.Tournament, error) {
+	tournament, err := service.TournamentRepo.GetTournamentByID(ctx, tournamentID)
+	if err == nil && tournament == nil {
+		return nil, ErrTournamentNotFound
+	}
+	return tournament, err
 }
 
-func (service *TournamentService) UpdateScore(userID uuid.UUID) error {
-	tournament, err := service.TournamentRepo.GetUserTournament(userID)
+func (service *TournamentService) UpdateScore(ctx context.Context, userID uuid.UUID) error {
+	tournament, err := service.TournamentRepo.GetUserTournament(ctx, userID)
 	if err != nil {
-		return errors.New("user is not in a tournament")
+		return err
+	}
+	if tournament == nil {
+		return ErrNotInTournament
+	}
+	if err := requireLife(ctx, service.UserRepo, service.Rules, userID); err != nil {
+		return err
 	}
 
 	// Increase the user's score.
-	err = service.TournamentRepo.IncreaseUserLevel(tournament.ID, userID)
+	return service.TournamentRepo.CompleteLevel(ctx, tournament.ID, userID)
+}
+
+// FailLevel records a failed level attempt, which costs the user a life and leaves the score as it is.
+func (service *TournamentService) FailLevel(ctx context.Context, userID uuid.UUID) (*models.Lives, error) {
+	return loseLife(ctx, service.UserRepo, service.Rules, userID)
+}
+
+// RefreshTournamentGauges updates the active tournament and fill ratio gauges from the database.
+func (service *TournamentService) RefreshTournamentGauges(ctx context.Context) {
+	stats, err := service.TournamentRepo.GetActiveTournamentStats(ctx)
 	if err != nil {
-		return err
+		service.Logger.WarnContext(ctx, "failed to refresh tournament gauges", "error", err)
+		return
 	}
-	return nil
-}
 
-func calculateReward(rank int) int {
-	switch {
-	case rank == 1:
-		return 5000
-	case rank == 2:
-		return 3000
-	case rank == 3:
-		return 2000
-	case rank >= 4 && rank <= 10:
-		return 1000
-	default:
-		return 0
+	for _, mode := range []string{models.TournamentModeSolo, models.TournamentModeTeam} {
+		metrics.ActiveTournaments.WithLabelValues(mode).Set(0)
+		metrics.TournamentFillRatio.WithLabelValues(mode).Set(0)
+	}
+	for _, stat := range stats {
+		metrics.ActiveTournaments.WithLabelValues(stat.Mode).Set(float64(stat.Active))
+		if stat.Seats > 0 {
+			metrics.TournamentFillRatio.WithLabelValues(stat.Mode).Set(float64(stat.Participants) / float64(stat.Seats))
+		}
 	}
 }
 
-func (service *TournamentService) FinishTournament(tournamentID uuid.UUID) error {
+// rejectEntry counts a rejected tournament entry and returns err.
+func rejectEntry(mode, reason string, err error) error {
+	metrics.TournamentEntriesRejected.WithLabelValues(mode, reason).Inc()
+	return err
+}
+
+// FinishTournament closes a tournament and pays out its rewards. It is safe to call more than once:
+// payouts run under a lock and continue from the last checkpoint, so a finalization that was
+// interrupted by a shutdown finishes without paying anyone twice.
+func (service *TournamentService) FinishTournament(ctx context.Context, tournamentID uuid.UUID) error {
+	tournament, err := service.TournamentRepo.GetTournamentByID(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+	if tournament == nil {
+		return ErrTournamentNotFound
+	}
+
 	// Mark the tournament as finished
-	err := service.TournamentRepo.FinishTournament(tournamentID)
+	err = service.TournamentRepo.FinishTournament(ctx, tournamentID)
 	if err != nil {
 		return err
 	}
 
-	// Fetch leaderboard from Redis
-	leaderboard, err := cache.GetTournamentLeaderboard(tournamentID, 35)
+	return service.payOut(ctx, tournament)
+}
+
+// ResumeFinalizations finishes the payouts of tournaments that were closed but not fully paid,
+// for example because the server stopped in the middle of a payout. Active tournaments are left alone.
+func (service *TournamentService) ResumeFinalizations(ctx context.Context) error {
+	ids, err := cache.GetPendingTournamentIDs(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Process rewards for top players
-	for rank, userIDstr := range leaderboard {
-		userID, err := uuid.Parse(userIDstr)
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		tournament, err := service.TournamentRepo.GetTournamentByID(ctx, id)
 		if err != nil {
+			return err
+		}
+		if tournament == nil || tournament.IsActive {
 			continue
 		}
 
-		// Determine the reward based on rank
-		reward := calculateReward(rank + 1)
+		service.Logger.InfoContext(ctx, "resuming tournament finalization", "tournament_id", id)
+		if err := service.payOut(ctx, tournament); err != nil && !errors.Is(err, ErrFinalizationInProgress) {
+			return err
+		}
+	}
+	return nil
+}
 
-		err = service.TournamentRepo.UpdateUserCoins(userID, reward)
-		if err != nil {
-			fmt.Println("Failed to update coins for user: ", userID, err)
+// Payouts hold the lock at most this long, so a crashed instance cannot block a tournament forever.
+const finalizationLockTTL = 10 * time.Minute
+
+// ErrTournamentNotFound is returned when finishing a tournament that does not exist.
+var ErrTournamentNotFound = newError(NotFound, "tournament_not_found", "tournament not found")
+
+// ErrFinalizationInProgress is returned when another finalization is paying out the same tournament.
+var ErrFinalizationInProgress = newError(Conflict, "tournament_finalization_in_progress", "tournament finalization is already in progress")
+
+// payOut pays out the rewards of a finished tournament under the finalization lock.
+func (service *TournamentService) payOut(ctx context.Context, tournament *models.Tournament) error {
+	token, locked, err := cache.AcquireFinalizationLock(ctx, tournament.ID, finalizationLockTTL)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return ErrFinalizationInProgress
+	}
+	// Cleanup must run even when ctx was cancelled by a shutdown
+	defer cache.ReleaseFinalizationLock(context.WithoutCancel(ctx), tournament.ID, token)
+	defer service.RefreshTournamentGauges(context.WithoutCancel(ctx))
+
+	mode := tournament.Mode
+	if mode != models.TournamentModeTeam {
+		mode = models.TournamentModeSolo
+	}
+	timer := prometheus.NewTimer(metrics.TournamentFinalizationDuration.WithLabelValues(mode))
+	defer timer.ObserveDuration()
+
+	if mode == models.TournamentModeTeam {
+		return service.payOutTeamTournament(ctx, tournament.ID)
+	}
+	return service.payOutSoloTournament(ctx, tournament.ID)
+}
+
+// payOutSoloTournament rewards the top players of a tournament, starting after the last checkpoint.
+// If ctx is cancelled, it stops after the current player and keeps the checkpoint for the next run.
+func (service *TournamentService) payOutSoloTournament(ctx context.Context, tournamentID uuid.UUID) error {
+	// Fetch leaderboard from Redis
+	leaderboard, err := cache.GetTournamentLeaderboard(ctx, tournamentID, service.Rules.TournamentSize)
+	if err != nil {
+		return err
+	}
+
+	paid, err := cache.GetPayoutCheckpoint(ctx, tournamentID)
+	if err != nil {
+		return err
+	}
+
+	// Process rewards for top players
+	for rank := paid; rank < len(leaderboard); rank++ {
+		if err := ctx.Err(); err != nil {
+			service.Logger.WarnContext(ctx, "tournament payout interrupted", "tournament_id", tournamentID, "paid_ranks", rank)
+			return err
 		}
 
-		// Top 10 players get a level-up
-		if rank < 10 {
-			err = service.TournamentRepo.IncreaseUserLevel(tournamentID, userID)
-			if err != nil {
-				fmt.Println("Failed to update level for user:", userID, err)
+		// A failed payment keeps the checkpoint, so the next run retries the player
+		userID, err := uuid.Parse(leaderboard[rank])
+		if err == nil {
+			if err := service.rewardPlayer(ctx, tournamentID, userID, rank); err != nil {
+				service.Logger.ErrorContext(ctx, "tournament payout failed", "tournament_id", tournamentID, "user_id", userID, "error", err)
+				return err
 			}
 		}
+
+		if err := cache.SetPayoutCheckpoint(ctx, tournamentID, rank+1); err != nil {
+			return err
+		}
+	}
+	cache.DeleteTournamentLeaderboard(ctx, tournamentID)
+	if err := cache.DeletePayoutCheckpoint(ctx, tournamentID); err != nil {
+		service.Logger.ErrorContext(ctx, "failed to delete payout checkpoint", "tournament_id", tournamentID, "error", err)
+	}
+
+	service.Logger.InfoContext(ctx, "tournament finished and rewards processed", "tournament_id", tournamentID)
+	return nil
+}
+
+// rewardPlayer pays the coin reward of a rank (0-based) and levels up the top ranks.
+// A player who was already paid, by an earlier interrupted run, or who deleted their account is skipped.
+func (service *TournamentService) rewardPlayer(ctx context.Context, tournamentID, userID uuid.UUID, rank int) error {
+	// Determine the reward based on rank
+	reward := service.Rules.Reward(rank + 1)
+	// Top players get a level-up
+	levelUp := rank < service.Rules.LevelUpRanks
+
+	err := service.TournamentRepo.PayReward(ctx, models.RewardPaidEvent{
+		TournamentID: tournamentID,
+		UserID:       userID,
+		Rank:         rank + 1,
+		Coins:        reward,
+	}, levelUp)
+	if errors.Is(err, repositories.ErrRewardAlreadyPaid) {
+		service.Logger.InfoContext(ctx, "tournament reward already paid", "tournament_id", tournamentID, "user_id", userID)
+		return nil
+	}
+	if errors.Is(err, repositories.ErrRewardUserNotFound) {
+		service.Logger.InfoContext(ctx, "skipping tournament reward of deleted user", "tournament_id", tournamentID, "user_id", userID)
+		return nil
+	}
+	if err != nil {
+		return err
 	}
-	cache.DeleteTournamentLeaderboard(tournamentID)
 
-	fmt.Println("Tournament finished and rewards processed", tournamentID)
+	metrics.CoinsPaidOut.WithLabelValues(models.TournamentModeSolo).Add(float64(reward))
+	if levelUp {
+		metrics.LevelUps.WithLabelValues("tournament_reward").Inc()
+	}
 	return nil
 }
 
-func (service *TournamentService) FinishAllTournaments() error {
+func (service *TournamentService) FinishAllTournaments(ctx context.Context) error {
 	var activeTournaments []models.Tournament
-	err := service.TournamentRepo.DB.Where("is_active = ?", true).Find(&activeTournaments).Error
+	err := service.TournamentRepo.DB.WithContext(ctx).Where("is_active = ?", true).Find(&activeTournaments).Error
 	if err != nil {
 		return err
 	}
 
 	if len(activeTournaments) == 0 {
-		fmt.Println("No active tournaments to finish")
+		service.Logger.InfoContext(ctx, "no active tournaments to finish")
 		return nil
 	}
 
 	for _, tournament := range activeTournaments {
-		err := service.FinishTournament(tournament.ID)
+		// Stop between tournaments when the request or the server is shutting down
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := service.FinishTournament(ctx, tournament.ID)
 		if err != nil {
-			fmt.Println("Failed to finish tournament: ", err)
+			service.Logger.ErrorContext(ctx, "failed to finish tournament", "tournament_id", tournament.ID, "error", err)
 		}
 	}
 
-	topPlayers, err := service.TournamentRepo.GetTopGlobalPlayers()
+	topPlayers, err := service.TournamentRepo.GetTopGlobalPlayers(ctx)
 	if err != nil {
-		fmt.Println("Failed to fetch global rankings: ", err)
+		service.Logger.ErrorContext(ctx, "failed to fetch global rankings", "error", err)
 		return err
 	}
 
-	fmt.Println("Top 1000 Players across all tournaments:")
 	for rank, user := range topPlayers {
-		fmt.Printf("%d. %s - Level: %d\n", rank+1, user.Username, user.Level)
+		service.Logger.DebugContext(ctx, "global ranking", "rank", rank+1, "username", user.Username, "level", user.Level)
 	}
 	return nil
 }
@@ -1,15 +1,34 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"good-api/internal/cache"
+	"good-api/internal/config"
+	"good-api/internal/countries"
+	"good-api/internal/metrics"
 	"good-api/internal/models"
 	"good-api/internal/repositories"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Errors returned by UserService.
+var (
+	ErrUsernameTaken       = newError(Conflict, "username_taken", "username is already taken")
+	ErrUsernameHeld        = newError(Conflict, "username_held", "username was recently given up by another user and is held for them")
+	ErrUsernameUnchanged   = newError(Invalid, "username_unchanged", "new username is the same as the current one")
+	ErrUsernameCooldown    = newError(Forbidden, "username_change_cooldown", "username was changed too recently, try again later")
+	ErrUsernameChangeCoins = newError(InsufficientFunds, "insufficient_coins", "not enough coins to change the username")
+	ErrUnknownCountry      = newError(Invalid, "unknown_country", "unknown country, use an ISO 3166-1 alpha-2 code")
+	ErrOutOfLives          = newError(Forbidden, "out_of_lives", "no lives left, wait for one to regenerate or refill them")
+	ErrLivesFull           = newError(Conflict, "lives_full", "lives are already full")
+	ErrLivesRefillCoins    = newError(InsufficientFunds, "insufficient_coins", "not enough coins to refill lives")
+)
+
 /*
 Service layer is responsible for business logic.
 It processes data before passing it to the repository or returning it to the handler.
@@ -19,21 +38,25 @@ It sits between the handler and the repository to ensure separation of concerns.
 // Service calls the repository to get or modify data.
 
 type UserService struct {
-	repo *repositories.UserRepository // Uses the repository
+	repo     *repositories.UserRepository // Uses the repository
+	teamRepo *repositories.TeamRepository
+	rules    config.GameConfig
+	logger   *slog.Logger
 }
 
 // NewUserService creates a new UserService.
-func NewUserService(userRepo *repositories.UserRepository) *UserService {
-	return &UserService{repo: userRepo}
+func NewUserService(userRepo *repositories.UserRepository, teamRepo *repositories.TeamRepository, rules config.GameConfig, logger *slog.Logger) *UserService {
+	return &UserService{repo: userRepo, teamRepo: teamRepo, rules: rules, logger: logger}
 }
 
 // CreateUser validates and creates a new user.
-func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
-	// Ensure username is unique
-	existingUser, _ := s.repo.GetUserByUsername(user.Username)
-	if existingUser != nil {
-		return nil, errors.New("username is already taken")
+func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	// Store the country as an ISO 3166-1 alpha-2 code
+	country, err := normalizeCountry(user.Country)
+	if err != nil {
+		return nil, err
 	}
+	user.Country = country
 
 	// Assign a new UUID
 	user.ID = uuid.New()
@@ -42,64 +65,290 @@ func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
 		user.Level = 1 // Default value if not provided
 	}
 	if user.Coins == 0 {
-		user.Coins = 1000 // Default value if not provided
+		user.Coins = s.rules.StartingCoins // Default value if not provided
 	}
+	user.Lives = s.rules.MaxLives
+	user.LivesUpdatedAt = time.Now().UTC()
 
-	createdUser, err := s.repo.CreateUser(user)
+	// The unique index on usernames rejects a taken name, even when two users ask for it at once
+	createdUser, err := s.repo.CreateUser(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, usernameError(err)
 	}
 	return createdUser, nil
 }
 
+// ChangeUsername renames a user for the configured coin cost. Changes are limited by a cooldown,
+// and the old username stays held for the user for a while so nobody else can take it over.
+func (s *UserService) ChangeUsername(ctx context.Context, userID uuid.UUID, username string) (*models.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+	if user.Username == username {
+		return nil, ErrUsernameUnchanged
+	}
+
+	updatedUser, err := s.repo.ChangeUsername(ctx, userID, username, s.rules.UsernameChangeCost, s.rules.UsernameChangeCooldown, s.rules.UsernameHoldPeriod)
+	if err != nil {
+		return nil, NotFoundAs(usernameError(err), ErrUserNotFound)
+	}
+	s.logger.InfoContext(ctx, "changed username", "user_id", userID, "cost", s.rules.UsernameChangeCost)
+	return updatedUser, nil
+}
+
+// usernameError turns the repository's username errors into domain errors.
+func usernameError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrUsernameTaken):
+		return ErrUsernameTaken
+	case errors.Is(err, repositories.ErrUsernameHeld):
+		return ErrUsernameHeld
+	case errors.Is(err, repositories.ErrUsernameCooldown):
+		return ErrUsernameCooldown
+	case errors.Is(err, repositories.ErrNotEnoughCoins):
+		return ErrUsernameChangeCoins
+	}
+	return err
+}
+
+// UserUpdate holds the user fields that can be changed directly. Nil fields are left as they are.
+// Coins and level are not here: they only change through gameplay.
+type UserUpdate struct {
+	Country *string
+}
+
 // UpdateUser updates a user's details.
-func (s *UserService) UpdateUser(user *models.User) (*models.User, error) {
+func (s *UserService) UpdateUser(ctx context.Context, userID uuid.UUID, update UserUpdate) (*models.User, error) {
 	// Ensure user exists
-	existingUser, err := s.repo.GetUserByID(user.ID)
+	existingUser, err := s.repo.GetUserByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, NotFoundAs(err, ErrUserNotFound)
 	}
-	fmt.Println("Attempting to update user with ID: ", user.ID)
+	s.logger.DebugContext(ctx, "updating user", "user_id", userID)
 
-	existingUser.Level = user.Level
-	existingUser.Coins = user.Coins
+	oldCountry := existingUser.Country
+	if update.Country != nil {
+		country, err := normalizeCountry(*update.Country)
+		if err != nil {
+			return nil, err
+		}
+		existingUser.Country = country
+	}
 
-	updatedUser, err := s.repo.UpdateUser(existingUser)
+	updatedUser, err := s.repo.UpdateUser(ctx, existingUser)
 	if err != nil {
 		return nil, err
 	}
+
+	if updatedUser.Country != oldCountry {
+		if err := cache.MoveUserCountry(ctx, updatedUser.ID, oldCountry, updatedUser.Country); err != nil {
+			s.logger.ErrorContext(ctx, "failed to move user between country leaderboards", "user_id", updatedUser.ID, "error", err)
+		}
+	}
+
+	s.syncGlobalLeaderboards(ctx, updatedUser)
 	return updatedUser, nil
 }
 
-// DeleteUser removes a user from the system
-func (s *UserService) DeleteUser(userID uuid.UUID) error {
-	_, err := s.repo.GetUserByID(userID)
+// DeleteUser soft deletes a user. The user leaves every leaderboard and their team at once,
+// and the account is erased for good by EraseDeletedUsers after the retention period.
+// The leaderboards are left first, so if Redis fails nothing is deleted and the request can be retried.
+func (s *UserService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return NotFoundAs(err, ErrUserNotFound)
+	}
+
+	if err := s.removeFromLeaderboards(ctx, user); err != nil {
+		return err
+	}
+
+	membership, err := s.teamRepo.GetMembership(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if membership != nil {
+		if err := leaveTeam(ctx, s.teamRepo, membership); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.DeleteUser(ctx, userID)
+}
+
+// removeFromLeaderboards removes a user from the global and country leaderboards, the leaderboard
+// of their active tournament and their contributions in every active team tournament,
+// so finished tournaments do not pay out to a deleted account.
+func (s *UserService) removeFromLeaderboards(ctx context.Context, user *models.User) error {
+	if err := cache.RemoveUserFromGlobalLeaderboards(ctx, user.ID, user.Country); err != nil {
+		return err
+	}
+
+	tournament, err := s.repo.GetUserTournament(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if tournament != nil {
+		if err := cache.RemoveUserFromLeaderboard(ctx, tournament.ID, user.ID); err != nil {
+			return err
+		}
+	}
+
+	// The user may have contributed to a team they have left since, so every entered team is checked
+	entries, err := s.teamRepo.GetActiveEntries(ctx)
+	if err != nil {
+		return err
+	}
+	teams := make(map[uuid.UUID][]uuid.UUID)
+	for _, entry := range entries {
+		teams[entry.TournamentID] = append(teams[entry.TournamentID], entry.TeamID)
+	}
+	for tournamentID, teamIDs := range teams {
+		if err := cache.RemoveTeamContributor(ctx, tournamentID, teamIDs, user.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportUser returns everything stored about a user.
+func (s *UserService) ExportUser(ctx context.Context, userID uuid.UUID) (*models.UserExport, error) {
+	export, err := s.repo.ExportUser(ctx, userID)
+	if err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+	return export, nil
+}
+
+// EraseDeletedUsers permanently erases up to limit users deleted longer than retention ago,
+// returning how many were erased.
+func (s *UserService) EraseDeletedUsers(ctx context.Context, retention time.Duration, limit int) (int, error) {
+	erased, err := s.repo.EraseDeletedUsers(ctx, time.Now().UTC().Add(-retention), limit)
+	if err != nil {
+		return 0, err
+	}
+	if erased > 0 {
+		metrics.UsersErased.Add(float64(erased))
+		s.logger.InfoContext(ctx, "erased deleted users", "count", erased)
+	}
+	return erased, nil
+}
+
+// GetLives returns the user's lives, with the lives regenerated so far counted in.
+func (s *UserService) GetLives(ctx context.Context, userID uuid.UUID) (*models.Lives, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return nil, NotFoundAs(err, ErrUserNotFound)
+	}
+	return models.NewLives(user, time.Now().UTC(), s.rules.MaxLives, s.rules.LifeRegenInterval), nil
+}
+
+// RefillLives gives the user all their lives back for the configured coin cost.
+func (s *UserService) RefillLives(ctx context.Context, userID uuid.UUID) (*models.Lives, error) {
+	user, err := s.repo.RefillLives(ctx, userID, s.rules.MaxLives, s.rules.LifeRegenInterval, s.rules.LivesRefillCost)
+	switch {
+	case errors.Is(err, repositories.ErrLivesFull):
+		return nil, ErrLivesFull
+	case errors.Is(err, repositories.ErrNotEnoughCoins):
+		return nil, ErrLivesRefillCoins
+	case err != nil:
+		return nil, NotFoundAs(err, ErrUserNotFound)
 	}
 
-	return s.repo.DeleteUser(userID)
+	metrics.LivesRefills.Inc()
+	return models.NewLives(user, time.Now().UTC(), s.rules.MaxLives, s.rules.LifeRegenInterval), nil
 }
 
-// IncreaseLevel increments the user's level.
-func (s *UserService) IncreaseLevel(userID uuid.UUID) error {
-	var user models.User
+// requireLife fails with ErrOutOfLives unless the user has a life to play a level with.
+// Completing a level does not cost a life, only failing one does.
+func requireLife(ctx context.Context, repo *repositories.UserRepository, rules config.GameConfig, userID uuid.UUID) error {
+	user, err := repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return NotFoundAs(err, ErrUserNotFound)
+	}
+	if lives, _ := models.RegenerateLives(user.Lives, user.LivesUpdatedAt, time.Now().UTC(), rules.MaxLives, rules.LifeRegenInterval); lives <= 0 {
+		return ErrOutOfLives
+	}
+	return nil
+}
 
-	if err := s.repo.DB.First(&user, "id = ?", userID).Error; err != nil {
-		return errors.New("user not found")
+// loseLife takes a life from the user for a failed level.
+func loseLife(ctx context.Context, repo *repositories.UserRepository, rules config.GameConfig, userID uuid.UUID) (*models.Lives, error) {
+	user, err := repo.LoseLife(ctx, userID, rules.MaxLives, rules.LifeRegenInterval)
+	if errors.Is(err, repositories.ErrNoLives) {
+		return nil, ErrOutOfLives
+	}
+	if err != nil {
+		return nil, NotFoundAs(err, ErrUserNotFound)
 	}
 
-	user.Level += 1
-	user.Coins += 100
+	metrics.LivesLost.Inc()
+	return models.NewLives(user, time.Now().UTC(), rules.MaxLives, rules.LifeRegenInterval), nil
+}
+
+// IncreaseLevel increments the user's level. The user needs a life to play the level.
+func (s *UserService) IncreaseLevel(ctx context.Context, userID uuid.UUID) error {
+	if err := requireLife(ctx, s.repo, s.rules, userID); err != nil {
+		return err
+	}
 
-	if err := s.repo.DB.Save(&user).Error; err != nil {
-		return errors.New("failed to update user's level and coins")
+	user, err := s.repo.LevelUp(ctx, userID, s.rules.LevelUpCoins)
+	if err != nil {
+		return NotFoundAs(err, ErrUserNotFound)
 	}
 
+	metrics.LevelUps.WithLabelValues("level_complete").Inc()
+
 	// Sync Redis leaderboard so the user ranking updates
-	tournament, err := s.repo.GetUserTournament(userID)
+	tournament, err := s.repo.GetUserTournament(ctx, userID)
 	if err == nil && tournament != nil {
-		cache.AddUserToLeaderboard(tournament.ID, user.ID, user.Level)
+		cache.AddUserToLeaderboard(ctx, tournament.ID, user.ID, user.Level)
 	}
+	s.syncGlobalLeaderboards(ctx, user)
+
+	// Level-ups also count towards the team score if the user's team is in a team tournament
+	s.addTeamContribution(ctx, userID)
 	return nil
 }
+
+// normalizeCountry converts a country name or code to its ISO 3166-1 alpha-2 code.
+// An empty country is stored as unknown.
+func normalizeCountry(country string) (string, error) {
+	if strings.TrimSpace(country) == "" {
+		return countries.Unknown, nil
+	}
+	code, ok := countries.Normalize(country)
+	if !ok {
+		return "", ErrUnknownCountry
+	}
+	return code, nil
+}
+
+// syncGlobalLeaderboards refreshes the user's level on the global and country leaderboards.
+func (s *UserService) syncGlobalLeaderboards(ctx context.Context, user *models.User) {
+	if err := cache.UpdateUserInGlobalLeaderboards(ctx, user.ID, user.Country, user.Level); err != nil {
+		s.logger.ErrorContext(ctx, "failed to update global leaderboards", "user_id", user.ID, "error", err)
+	}
+}
+
+// addTeamContribution adds a level-up to the score of the user's team in its active team tournament.
+func (s *UserService) addTeamContribution(ctx context.Context, userID uuid.UUID) {
+	if s.teamRepo == nil {
+		return
+	}
+
+	member, err := s.teamRepo.GetMembership(ctx, userID)
+	if err != nil || member == nil {
+		return
+	}
+
+	entry, err := s.teamRepo.GetActiveEntry(ctx, member.TeamID)
+	if err != nil || entry == nil {
+		return
+	}
+
+	if err := cache.AddTeamContribution(ctx, entry.TournamentID, member.TeamID, userID, 1); err != nil {
+		s.logger.ErrorContext(ctx, "failed to add team contribution", "user_id", userID, "error", err)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"slices"
+
+	"good-api/internal/config"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"good-api/internal/webhooks"
+
+	"github.com/google/uuid"
+)
+
+// Errors returned by WebhookService.
+var (
+	ErrWebhookInvalidURL           = newError(Invalid, "webhook_invalid_url", "webhook URL must be an absolute https URL")
+	ErrWebhookPrivateURL           = newError(Invalid, "webhook_private_url", "webhook URL must not point at a loopback, link-local or private address")
+	ErrWebhookNoEventTypes         = newError(Invalid, "webhook_no_event_types", "subscribe to at least one event type")
+	ErrWebhookUnknownEventType     = newError(Invalid, "webhook_unknown_event_type", "unknown event type")
+	ErrWebhookSubscriptionNotFound = newError(NotFound, "webhook_subscription_not_found", "webhook subscription not found")
+	ErrWebhookDeadLetterNotFound   = newError(NotFound, "webhook_dead_letter_not_found", "dead letter not found")
+	ErrWebhookAlreadyReplayed      = newError(Conflict, "webhook_already_replayed", "dead letter was already replayed")
+)
+
+type WebhookService struct {
+	repo *repositories.WebhookRepository
+	// Lets subscriptions use http and private addresses, see webhooks.CheckURL
+	allowInsecureURLs bool
+}
+
+func NewWebhookService(repo *repositories.WebhookRepository, cfg config.WebhooksConfig) *WebhookService {
+	if repo == nil {
+		panic("WebhookService: Repository must not be nil")
+	}
+	return &WebhookService{repo: repo, allowInsecureURLs: cfg.AllowInsecureURLs}
+}
+
+// CreateSubscription subscribes a URL to event types. Without a secret, a random one is generated.
+func (s *WebhookService) CreateSubscription(ctx context.Context, rawURL string, eventTypes []string, secret string) (*models.WebhookSubscription, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrWebhookInvalidURL
+	}
+	if !s.allowInsecureURLs {
+		if err := webhooks.CheckURL(rawURL); errors.Is(err, webhooks.ErrPrivateAddress) {
+			return nil, ErrWebhookPrivateURL
+		} else if err != nil {
+			return nil, ErrWebhookInvalidURL
+		}
+	}
+
+	var types []string
+	for _, eventType := range eventTypes {
+		if !slices.Contains(models.EventTypes, eventType) {
+			return nil, ErrWebhookUnknownEventType
+		}
+		if !slices.Contains(types, eventType) {
+			types = append(types, eventType)
+		}
+	}
+	if len(types) == 0 {
+		return nil, ErrWebhookNoEventTypes
+	}
+
+	if secret == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(key)
+	}
+
+	subscription := &models.WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        rawURL,
+		EventTypes: types,
+		Secret:     secret,
+	}
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions returns every webhook subscription.
+func (s *WebhookService) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.repo.GetSubscriptions(ctx)
+}
+
+// DeleteSubscription removes a subscription. Its pending deliveries are dropped.
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	deleted, err := s.repo.DeleteSubscription(ctx, subscriptionID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookSubscriptionNotFound
+	}
+	return nil
+}
+
+// GetDeadLetters returns the most recent failed deliveries.
+func (s *WebhookService) GetDeadLetters(ctx context.Context, limit int, includeReplayed bool) ([]models.WebhookDeadLetter, error) {
+	return s.repo.GetDeadLetters(ctx, limit, includeReplayed)
+}
+
+// ReplayDeadLetter queues a failed delivery again with a fresh set of attempts.
+func (s *WebhookService) ReplayDeadLetter(ctx context.Context, deadLetterID uuid.UUID) (*models.WebhookDeadLetter, error) {
+	deadLetter, err := s.repo.GetDeadLetter(ctx, deadLetterID)
+	if err != nil {
+		return nil, err
+	}
+	if deadLetter == nil {
+		return nil, ErrWebhookDeadLetterNotFound
+	}
+
+	subscription, err := s.repo.GetSubscription(ctx, deadLetter.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrWebhookSubscriptionNotFound
+	}
+
+	replayed, err := s.repo.ReplayDeadLetter(ctx, deadLetter)
+	if err != nil {
+		return nil, err
+	}
+	if !replayed {
+		return nil, ErrWebhookAlreadyReplayed
+	}
+	return s.repo.GetDeadLetter(ctx, deadLetterID)
+}
@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+/*
+Subscriptions are registered by operators but point at partner servers, so a subscription
+URL must not be turned into a way to reach the internal network (SSRF). URLs must use
+https and must not name a loopback, link-local or private address, and the deliverer
+refuses to connect to such addresses even when a public host name resolves to one.
+AllowInsecureURLs lifts both checks for local development and tests.
+*/
+
+// ErrPrivateAddress is returned when a webhook would be sent to a non-public address.
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// PublicAddress reports whether addr may receive webhooks.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && addr.IsGlobalUnicast() && !addr.IsPrivate() &&
+		!addr.IsLoopback() && !addr.IsLinkLocalUnicast()
+}
+
+// CheckURL returns an error if rawURL is not an https URL of a public host. Host names are
+// only resolved when delivering, where the resolved addresses are checked.
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("webhook URL %q must be an absolute https URL", rawURL)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !PublicAddress(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// dialPublicOnly is a net.Dialer Control function that refuses connections to addresses
+// that are not public, after the host name was resolved.
+func dialPublicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !PublicAddress(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package webhooks
+
+/*
+Outbox events reach partner webhooks in two steps. The Sink, one of the outbox sinks,
+queues a delivery for every subscription of the event's type. The Deliverer then posts
+each due delivery, signed with the subscription's secret, and retries failures with
+exponential backoff until it moves them to the dead-letter table.
+
+Receivers verify a delivery by computing
+	hex(HMAC-SHA256(secret, timestamp + "." + body))
+with the X-Webhook-Timestamp header, and comparing it to the X-Webhook-Signature header,
+which is that value prefixed with "sha256=". Old timestamps should be rejected to stop replays.
+*/
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/metrics"
+	"good-api/internal/models"
+	"good-api/internal/outbox"
+	"good-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Headers of a webhook delivery, next to the outbox event ID and type headers.
+const (
+	SignatureHeader  = "X-Webhook-Signature"
+	TimestampHeader  = "X-Webhook-Timestamp"
+	DeliveryIDHeader = "X-Webhook-Delivery"
+)
+
+// Claimed deliveries are hidden from other workers this long, so a delivery whose
+// worker died in the middle of it is retried afterwards.
+const claimLease = 5 * time.Minute
+
+// Sign returns the signature header value of a body sent at timestamp (Unix seconds).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sink is the outbox sink that queues an event for every webhook subscribed to its type.
+type Sink struct {
+	repo *repositories.WebhookRepository
+}
+
+// NewSink creates the webhook subscription sink.
+func NewSink(repo *repositories.WebhookRepository) *Sink {
+	return &Sink{repo: repo}
+}
+
+func (s *Sink) Name() string {
+	return "webhooks"
+}
+
+func (s *Sink) Deliver(ctx context.Context, envelope outbox.Envelope) error {
+	subscriptions, err := s.repo.GetSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(envelope.Type) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: subscription.ID,
+			EventID:        envelope.ID,
+			EventType:      envelope.Type,
+			Payload:        string(body),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+	return s.repo.AddDeliveries(ctx, deliveries)
+}
+
+// Deliverer posts queued webhook deliveries.
+type Deliverer struct {
+	repo   *repositories.WebhookRepository
+	client *http.Client
+	cfg    config.WebhooksConfig
+	logger *slog.Logger
+}
+
+// NewDeliverer creates a deliverer that sends deliveries with the configured timeout and retries.
+// Unless cfg.AllowInsecureURLs is set, it only connects to public addresses.
+func NewDeliverer(repo *repositories.WebhookRepository, cfg config.WebhooksConfig, logger *slog.Logger) *Deliverer {
+	client := &http.Client{Timeout: cfg.Timeout}
+	if !cfg.AllowInsecureURLs {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{Timeout: cfg.Timeout, Control: dialPublicOnly}).DialContext
+		client.Transport = transport
+		// Redirects could lead to a plain http or internal URL
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	}
+	return &Deliverer{repo: repo, client: client, cfg: cfg, logger: logger}
+}
+
+// BatchSize is the most deliveries DeliverDue handles at once.
+func (d *Deliverer) BatchSize() int {
+	return d.cfg.BatchSize
+}
+
+// DeliverDue claims a batch of due deliveries and sends them, returning how many were claimed.
+func (d *Deliverer) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDue(ctx, d.cfg.BatchSize, claimLease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	subscriptions := make(map[uuid.UUID]*models.WebhookSubscription)
+	for _, delivery := range deliveries {
+		if err := ctx.Err(); err != nil {
+			// The rest of the batch is claimed again when the lease ends
+			return len(deliveries), err
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil {
+				return len(deliveries), err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		d.deliver(ctx, subscription, delivery)
+	}
+	return len(deliveries), nil
+}
+
+func (d *Deliverer) deliver(ctx context.Context, subscription *models.WebhookSubscription, delivery models.WebhookDelivery) {
+	var statusCode int
+	var err error
+	if subscription == nil {
+		err = fmt.Errorf("subscription %s no longer exists", delivery.SubscriptionID)
+	} else {
+		statusCode, err = d.post(ctx, subscription, delivery)
+	}
+
+	// Recording the outcome must happen even if ctx was cancelled during the request
+	recordCtx := context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+		metrics.WebhookDeliveries.WithLabelValues("delivered").Inc()
+		err = d.repo.MarkDelivered(recordCtx, delivery.ID, statusCode)
+	case subscription == nil || delivery.Attempts >= d.cfg.MaxAttempts:
+		metrics.WebhookDeliveries.WithLabelValues("dead_letter").Inc()
+		d.logger.WarnContext(ctx, "webhook delivery dead-lettered", "delivery_id", delivery.ID, "subscription_id", delivery.SubscriptionID, "attempts", delivery.Attempts, "error", err)
+		err = d.repo.DeadLetter(recordCtx, delivery, statusCode, err.Error())
+	default:
+		metrics.WebhookDeliveries.WithLabelValues("retry").Inc()
+		retryAt := time.Now().UTC().Add(d.Backoff(delivery.Attempts))
+		d.logger.InfoContext(ctx, "webhook delivery failed, retrying", "delivery_id", delivery.ID, "attempt", delivery.Attempts, "retry_at", retryAt, "error", err)
+		err = d.repo.MarkRetry(recordCtx, delivery.ID, statusCode, err.Error(), retryAt)
+	}
+	if err != nil {
+		d.logger.ErrorContext(ctx, "failed to record webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// post sends a delivery and returns the receiver's status code. Any status other than 2xx is an error.
+func (d *Deliverer) post(ctx context.Context, subscription *models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(outbox.EventIDHeader, delivery.EventID.String())
+	req.Header.Set(outbox.EventTypeHeader, delivery.EventType)
+	req.Header.Set(DeliveryIDHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff returns how long to wait before the next attempt after the given number of attempts.
+func (d *Deliverer) Backoff(attempts int) time.Duration {
+	wait := d.cfg.BaseBackoff
+	for i := 1; i < attempts && wait < d.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.cfg.MaxBackoff)
+}
@@ -0,0 +1,68 @@
+package workers
+
+/*
+Background workers run next to the HTTP server. Each worker gets two contexts:
+stopping is cancelled when shutdown starts, after which the worker must not start new work,
+and work is cancelled only when the shutdown deadline passes, so the work in progress
+can finish in time or checkpoint and return.
+*/
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Group runs background workers and stops them on shutdown.
+type Group struct {
+	logger *slog.Logger
+
+	stopping context.Context
+	stop     context.CancelFunc
+	work     context.Context
+	abort    context.CancelFunc
+
+	wg sync.WaitGroup
+}
+
+// NewGroup creates a worker group. Work contexts derive from parent.
+func NewGroup(parent context.Context, logger *slog.Logger) *Group {
+	stopping, stop := context.WithCancel(context.Background())
+	work, abort := context.WithCancel(parent)
+	return &Group{logger: logger, stopping: stopping, stop: stop, work: work, abort: abort}
+}
+
+// Go starts a worker. run must return soon after stopping is cancelled.
+func (g *Group) Go(name string, run func(stopping, work context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.logger.Info("worker started", "worker", name)
+		run(g.stopping, g.work)
+		g.logger.Info("worker stopped", "worker", name)
+	}()
+}
+
+// Shutdown stops the workers from starting new work and waits for them.
+// If ctx expires first, the work in progress is cancelled so it checkpoints,
+// and Shutdown waits for the workers to return before it reports ctx's error.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.stop()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		g.abort()
+		return nil
+	case <-ctx.Done():
+		g.logger.Warn("shutdown deadline passed, interrupting workers")
+		g.abort()
+		<-done
+		return ctx.Err()
+	}
+}
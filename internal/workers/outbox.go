@@ -0,0 +1,48 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"good-api/internal/outbox"
+	"good-api/internal/webhooks"
+)
+
+// DispatchOutbox returns a worker that delivers the domain events of the outbox.
+func DispatchOutbox(dispatcher *outbox.Dispatcher, logger *slog.Logger, interval time.Duration) func(stopping, work context.Context) {
+	return drainBatches(dispatcher.DispatchPending, dispatcher.BatchSize(), interval, func(ctx context.Context, err error) {
+		logger.ErrorContext(ctx, "failed to dispatch outbox events", "error", err)
+	})
+}
+
+// DeliverWebhooks returns a worker that sends the queued webhook deliveries.
+func DeliverWebhooks(deliverer *webhooks.Deliverer, logger *slog.Logger, interval time.Duration) func(stopping, work context.Context) {
+	return drainBatches(deliverer.DeliverDue, deliverer.BatchSize(), interval, func(ctx context.Context, err error) {
+		logger.ErrorContext(ctx, "failed to deliver webhooks", "error", err)
+	})
+}
+
+// drainBatches returns a worker that runs batch for as long as it returns full batches,
+// and polls every interval once the queue is empty.
+func drainBatches(batch func(context.Context) (int, error), batchSize int, interval time.Duration, onError func(context.Context, error)) func(stopping, work context.Context) {
+	return func(stopping, work context.Context) {
+		for {
+			claimed, err := batch(work)
+			if err != nil {
+				onError(work, err)
+			}
+
+			wait := interval
+			if err == nil && claimed == batchSize {
+				wait = 0
+			}
+
+			select {
+			case <-stopping.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}
+}
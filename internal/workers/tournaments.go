@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"good-api/internal/services"
+)
+
+// ResumeFinalizations returns a worker that finishes interrupted tournament payouts,
+// once at startup and then every interval.
+func ResumeFinalizations(service *services.TournamentService, logger *slog.Logger, interval time.Duration) func(stopping, work context.Context) {
+	return func(stopping, work context.Context) {
+		for {
+			if err := service.ResumeFinalizations(work); err != nil {
+				logger.ErrorContext(work, "failed to resume tournament finalizations", "error", err)
+			}
+
+			select {
+			case <-stopping.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+// FinishTournamentsDaily returns a worker that finishes all active tournaments when
+// the UTC day ends, replacing the external cron job.
+func FinishTournamentsDaily(service *services.TournamentService, logger *slog.Logger) func(stopping, work context.Context) {
+	return func(stopping, work context.Context) {
+		for {
+			wait := time.Until(nextDayEnd(time.Now().UTC()))
+			logger.Info("next tournament finalization scheduled", "in", wait.Round(time.Second).String())
+
+			select {
+			case <-stopping.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			if err := service.FinishAllTournaments(work); err != nil {
+				logger.ErrorContext(work, "scheduled tournament finalization failed", "error", err)
+			}
+		}
+	}
+}
+
+// nextDayEnd returns the next 23:59 UTC, when the daily tournaments end.
+func nextDayEnd(now time.Time) time.Time {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 0, 0, time.UTC)
+	if !end.After(now) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
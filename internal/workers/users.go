@@ -0,0 +1,19 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+
+	"good-api/internal/config"
+	"good-api/internal/services"
+)
+
+// EraseDeletedUsers returns a worker that erases deleted users once their retention period has passed.
+func EraseDeletedUsers(service *services.UserService, cfg config.PrivacyConfig, logger *slog.Logger) func(stopping, work context.Context) {
+	erase := func(ctx context.Context) (int, error) {
+		return service.EraseDeletedUsers(ctx, cfg.ErasureRetention, cfg.ErasureBatchSize)
+	}
+	return drainBatches(erase, cfg.ErasureBatchSize, cfg.ErasureInterval, func(ctx context.Context, err error) {
+		logger.ErrorContext(ctx, "failed to erase deleted users", "error", err)
+	})
+}
@@ -1,19 +1,38 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"good-api/internal/cache"
+	"good-api/internal/config"
 	"good-api/internal/database"
+	"good-api/internal/grpcapi"
 	"good-api/internal/handlers"
+	"good-api/internal/logging"
+	"good-api/internal/middleware"
+	"good-api/internal/outbox"
+	"good-api/internal/ratelimit"
 	"good-api/internal/repositories"
 	"good-api/internal/routes"
 	"good-api/internal/services"
-	"log"
+	"good-api/internal/webhooks"
+	"good-api/internal/workers"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "good-api/docs"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
 )
 
 // @title Good Blast Match 3 REST API
@@ -23,38 +42,230 @@ import (
 // @BasePath /
 func main() {
 
+	// Load Config from the config file, environment and flags
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Initialize Logger
+	logger := logging.New(cfg.Log.Format, cfg.Log.Level)
+
 	// Initialize Database
-	database, err := database.InitDB()
+	repositories.QueryTimeout = cfg.Database.QueryTimeout
+	db, err := database.InitDB(cfg.Database, logger)
 	if err != nil {
-		log.Fatalf("Failed to connect to the database: %v", err)
+		logger.Error("failed to connect to the database", "error", err)
+		os.Exit(1)
 	}
 
 	// Initialize Redis
-	cache.InitRedis()
+	cache.InitRedis(cfg.Redis, logger)
 
 	// Initialize User components
-	userRepo := repositories.NewUserRepository(database)
-	userService := services.NewUserService(userRepo)
+	userRepo := repositories.NewCachedUserRepository(db, logger, cfg.Redis.ProfileTTL)
+	teamRepo := repositories.NewTeamRepository(db, logger)
+	userService := services.NewUserService(userRepo, teamRepo, cfg.Game, logger)
 	userHandler := handlers.NewUserHandlerwithService(userRepo, userService)
 	userJustHandler := handlers.NewUserHandlerwithRepo(userRepo)
 
 	// Initialize Tournament components
-	tournamentRepo := repositories.NewTournamentRepository(database)
-	tournamentService := services.NewTournamentService(tournamentRepo, userRepo)
+	tournamentRepo := repositories.NewTournamentRepository(db, logger)
+	tournamentService := services.NewTournamentService(tournamentRepo, userRepo, teamRepo, cfg.Game, logger)
 	tournamentHandler := handlers.NewTournamentHandler(tournamentService, tournamentRepo)
 
+	// Initialize Team components
+	teamService := services.NewTeamService(teamRepo, userRepo)
+	teamHandler := handlers.NewTeamHandler(teamService)
+
+	// Initialize Friend components
+	friendRepo := repositories.NewFriendRepository(db)
+	friendService := services.NewFriendService(friendRepo, userRepo)
+	friendHandler := handlers.NewFriendHandler(friendService)
+
 	// Initialize Leaderboard components
-	leaderboardRepo := repositories.NewLeaderboardRepository(database)
-	leaderboardService := services.NewLeaderboardService(leaderboardRepo)
+	leaderboardRepo := repositories.NewLeaderboardRepository(db)
+	leaderboardService := services.NewLeaderboardService(leaderboardRepo, userRepo, logger)
 	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService, leaderboardRepo)
 
-	go cache.SyncLeaderboardsToDB(tournamentRepo, userService)
+	// Initialize Country components
+	countryHandler := handlers.NewCountryHandler(services.NewCountryService())
+
+	// Initialize Health components
+	healthService := services.NewHealthService(db)
+	healthHandler := handlers.NewHealthHandler(healthService)
+
+	// Initialize Admin components
+	adminHandler := handlers.NewAdminHandler(cfg)
+
+	// Initialize Webhook components
+	webhookRepo := repositories.NewWebhookRepository(db)
+	webhookHandler := handlers.NewWebhookHandler(services.NewWebhookService(webhookRepo, cfg.Webhooks))
+	webhookDeliverer := webhooks.NewDeliverer(webhookRepo, cfg.Webhooks, logger)
+
+	// Initialize Shop components
+	shopHandler := handlers.NewShopHandler(services.NewShopService(repositories.NewShopRepository(db, logger), userRepo, logger))
+
+	// Initialize Daily Reward components
+	dailyRewardService := services.NewDailyRewardService(repositories.NewDailyRewardRepository(db, logger), userRepo, cfg.Game, logger)
+	dailyRewardHandler := handlers.NewDailyRewardHandler(dailyRewardService)
+
+	// Initialize Outbox components
+	outboxSinks, err := outbox.NewSinks(cfg.Outbox)
+	if err != nil {
+		logger.Error("failed to create outbox sinks", "error", err)
+		os.Exit(1)
+	}
+	if cfg.Webhooks.Enabled {
+		outboxSinks = append(outboxSinks, webhooks.NewSink(webhookRepo))
+	}
+	outboxDispatcher := outbox.NewDispatcher(repositories.NewOutboxRepository(db), outboxSinks, cfg.Outbox, logger)
+
+	// Cancelled when signalled to stop; a second signal kills the process
+	ctx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	// Cancelled when the shutdown deadline passes, so in-flight requests and workers checkpoint
+	baseCtx, abort := context.WithCancel(context.Background())
+	defer abort()
+
+	// Rebuild the global and country leaderboards if Redis lost them or a migration changed their data
+	if database.LeaderboardRebuildRequired {
+		if _, err := leaderboardService.RebuildLeaderboards(ctx); err != nil {
+			logger.Error("failed to rebuild leaderboards", "error", err)
+		}
+	} else if err := leaderboardService.EnsureLeaderboards(ctx); err != nil {
+		logger.Error("failed to rebuild leaderboards", "error", err)
+	}
+	tournamentService.RefreshTournamentGauges(ctx)
+
+	// Start background workers
+	workerGroup := workers.NewGroup(baseCtx, logger)
+	workerGroup.Go("finalization-resume", workers.ResumeFinalizations(tournamentService, logger, cfg.Workers.ResumeInterval))
+	if cfg.Workers.SchedulerEnabled {
+		workerGroup.Go("tournament-scheduler", workers.FinishTournamentsDaily(tournamentService, logger))
+	}
+	if cfg.Outbox.Enabled && len(outboxSinks) > 0 {
+		workerGroup.Go("outbox-dispatcher", workers.DispatchOutbox(outboxDispatcher, logger, cfg.Outbox.PollInterval))
+	} else {
+		logger.Info("outbox dispatcher disabled, domain events stay in the outbox")
+	}
+	if cfg.Webhooks.Enabled {
+		workerGroup.Go("webhook-deliverer", workers.DeliverWebhooks(webhookDeliverer, logger, cfg.Webhooks.PollInterval))
+	}
+	workerGroup.Go("user-eraser", workers.EraseDeletedUsers(userService, cfg.Privacy, logger))
 
 	// Setup Router
-	router := gin.Default()
-	routes.SetupRoutes(router, userHandler, userJustHandler, tournamentHandler, leaderboardHandler)
+	router := gin.New()
+	router.Use(gin.Recovery(), middleware.RequestLogger(logger), middleware.Metrics(), middleware.Timeout(cfg.Server.RequestTimeout))
+	limiter := ratelimit.New(cfg.RateLimit, logger)
+	routes.SetupRoutes(router, userHandler, userJustHandler, tournamentHandler, leaderboardHandler, teamHandler, friendHandler, countryHandler, healthHandler, adminHandler, webhookHandler, shopHandler, dailyRewardHandler, middleware.Idempotency(cfg.Server.IdempotencyTTL, logger), middleware.NewLimits(limiter, cfg.RateLimit), middleware.AdminAuth(cfg.Server.AdminToken))
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	// Start Server
-	router.Run(":8080")
+	server := &http.Server{
+		Addr:        cfg.Server.Addr,
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server listening", "addr", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	// Start the gRPC server next to the REST API
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		grpcServer = grpcapi.New(grpcapi.Services{
+			UserService:        userService,
+			UserRepo:           userRepo,
+			TournamentService:  tournamentService,
+			TournamentRepo:     tournamentRepo,
+			LeaderboardService: leaderboardService,
+			Limiter:            limiter,
+		}, cfg, logger)
+		listener, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			logger.Error("failed to listen for gRPC", "addr", cfg.GRPC.Addr, "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			logger.Info("gRPC server listening", "addr", cfg.GRPC.Addr)
+			serverErr <- grpcServer.Serve(listener)
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("server failed", "error", err)
+		}
+	case <-ctx.Done():
+		logger.Info("shutdown signal received, draining")
+	}
+
+	shutdown(server, grpcServer, workerGroup, healthService, abort, cfg.Server.ShutdownTimeout, logger)
+}
+
+// shutdown stops accepting requests and work, waits for in-flight requests and workers
+// until timeout, then interrupts whatever is left so it can checkpoint.
+func shutdown(server *http.Server, grpcServer *grpc.Server, workerGroup *workers.Group, healthService *services.HealthService, abort context.CancelFunc, timeout time.Duration, logger *slog.Logger) {
+	healthService.SetDraining()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Workers stop taking new work while the server drains
+	workersDone := make(chan error, 1)
+	go func() { workersDone <- workerGroup.Shutdown(ctx) }()
+
+	grpcDone := make(chan struct{})
+	go func() {
+		defer close(grpcDone)
+		stopGRPC(ctx, grpcServer, logger)
+	}()
+
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Warn("shutdown deadline passed, interrupting in-flight requests", "error", err)
+		abort()
+		server.Close()
+	}
+	<-grpcDone
+	if err := <-workersDone; err != nil {
+		logger.Warn("workers were interrupted", "error", err)
+	}
+	abort()
+
+	if err := cache.Close(); err != nil {
+		logger.Error("failed to close Redis client", "error", err)
+	}
+	if err := database.Close(); err != nil {
+		logger.Error("failed to close database", "error", err)
+	}
+	logger.Info("shutdown complete")
+}
+
+// stopGRPC waits for in-flight gRPC calls until ctx is done, then closes the connections that are
+// left. Open leaderboard streams only end at that point, when their clients are expected to reconnect.
+func stopGRPC(ctx context.Context, server *grpc.Server, logger *slog.Logger) {
+	if server == nil {
+		return
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Warn("shutdown deadline passed, closing gRPC connections")
+		server.Stop()
+		<-stopped
+	}
 }
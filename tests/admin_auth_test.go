@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"good-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	newRouter := func(token string) *gin.Engine {
+		router := gin.New()
+		router.GET("/admin/config", middleware.AdminAuth(token), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+	send := func(router *gin.Engine, authorization string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/admin/config", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	router := newRouter("s3cret")
+	assert.Equal(t, http.StatusOK, send(router, "Bearer s3cret").Code)
+
+	rec := send(router, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "unauthorized", getProblem(t, rec).Code)
+	assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, send(router, "Bearer wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(router, "s3cret").Code, "only bearer tokens are accepted")
+
+	// Without a configured token the admin API is closed
+	closed := newRouter("")
+	assert.Equal(t, http.StatusUnauthorized, send(closed, "Bearer ").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(closed, "").Code)
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+server:
+  addr: ":9000"
+  request_timeout: 3s
+database:
+  host: file-host
+  max_open_conns: 40
+redis:
+  db: 2
+  tls: true
+game:
+  entry_fee: 700
+  rewards: [100, 50]
+`), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("GAME_ENTRY_FEE", "800")
+	t.Setenv("REDIS_DB", "3")
+
+	cfg, err := config.Load([]string{"-config", path, "-redis-db", "4"})
+	require.NoError(t, err)
+
+	// The file overrides the defaults
+	assert.Equal(t, ":9000", cfg.Server.Addr)
+	assert.Equal(t, 3*time.Second, cfg.Server.RequestTimeout)
+	assert.Equal(t, 40, cfg.Database.MaxOpenConns)
+	assert.True(t, cfg.Redis.TLS)
+	assert.Equal(t, []int{100, 50}, cfg.Game.Rewards)
+	assert.Equal(t, 100, cfg.Game.Reward(1))
+	assert.Equal(t, 0, cfg.Game.Reward(3))
+
+	// The environment overrides the file, and flags override the environment
+	assert.Equal(t, "env-host", cfg.Database.Host)
+	assert.Equal(t, 800, cfg.Game.EntryFee)
+	assert.Equal(t, 4, cfg.Redis.DB)
+
+	// Settings set nowhere keep their default
+	assert.Equal(t, config.Default().Game.TournamentSize, cfg.Game.TournamentSize)
+}
+
+func TestConfigValidation(t *testing.T) {
+	t.Setenv("REDIS_DB", "42")
+	t.Setenv("DB_MAX_IDLE_CONNS", "100")
+
+	_, err := config.Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redis.db")
+	assert.Contains(t, err.Error(), "database.max_idle_conns")
+
+	t.Setenv("REDIS_DB", "not-a-number")
+	_, err = config.Load(nil)
+	assert.ErrorContains(t, err, "REDIS_DB")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("databse:\n  host: typo\n"), 0o600))
+	_, err = config.Load([]string{"-config", path})
+	assert.Error(t, err, "unknown settings in the file are rejected")
+}
+
+func TestAdminConfigRedactsSecrets(t *testing.T) {
+	router := SetupRouter()
+
+	rec := SendJSON(router, "GET", "/admin/config", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "the admin API needs the admin token")
+
+	rec = SendAdminJSON(router, "GET", "/admin/config", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), testConfig.Database.Password)
+	assert.NotContains(t, rec.Body.String(), testConfig.Server.AdminToken)
+
+	var body map[string]map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, logging.Redacted, body["database"]["password"])
+	assert.Equal(t, testConfig.Database.Host, body["database"]["host"])
+	assert.Equal(t, "10s", body["server"]["request_timeout"])
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/logging"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"good-api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyRewardCalendar(t *testing.T) {
+	rules := config.GameConfig{DailyRewards: []int{100, 200, 500}}
+	assert.Equal(t, 0, rules.DailyReward(0))
+	assert.Equal(t, 100, rules.DailyReward(1))
+	assert.Equal(t, 500, rules.DailyReward(3))
+	assert.Equal(t, 100, rules.DailyReward(4), "the calendar starts over after the last day")
+	assert.Equal(t, 200, rules.DailyReward(8))
+
+	late := time.Date(2024, 3, 1, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), models.UTCDay(late))
+}
+
+// newDailyRewardService returns a service whose clock is read from *now.
+func newDailyRewardService(now *time.Time) *services.DailyRewardService {
+	db := SetupTestDB()
+	logger := logging.Discard()
+	rules := testConfig.Game
+	rules.DailyRewards = []int{100, 150, 200}
+	service := services.NewDailyRewardService(repositories.NewDailyRewardRepository(db, logger), repositories.NewUserRepository(db, logger), rules, logger)
+	service.Now = func() time.Time { return *now }
+	return service
+}
+
+func TestClaimDailyRewardStreak(t *testing.T) {
+	db := SetupTestDB()
+	user, _ := SeedTestData(db)
+	ctx := context.Background()
+	now := time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC)
+	service := newDailyRewardService(&now)
+
+	reward, err := service.Claim(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.False(t, reward.AlreadyClaimed)
+	assert.Equal(t, 1, reward.Claim.Streak)
+	assert.Equal(t, 100, reward.Claim.Coins)
+	assert.Equal(t, user.Coins+100, reward.Balance)
+
+	// Later the same UTC day nothing is paid again
+	now = time.Date(2024, 5, 10, 23, 59, 0, 0, time.UTC)
+	again, err := service.Claim(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, again.AlreadyClaimed)
+	assert.Equal(t, reward.Claim.ID, again.Claim.ID)
+	assert.Equal(t, user.Coins+100, again.Balance)
+
+	// Consecutive days grow the streak, and the calendar wraps after its last day
+	balance := user.Coins + 100
+	for day, want := range []int{150, 200, 100} {
+		now = time.Date(2024, 5, 11+day, 0, 1, 0, 0, time.UTC)
+		reward, err = service.Claim(ctx, user.ID)
+		assert.NoError(t, err)
+		balance += want
+		assert.Equal(t, day+2, reward.Claim.Streak)
+		assert.Equal(t, want, reward.Claim.Coins)
+		assert.Equal(t, balance, reward.Balance)
+	}
+
+	status, err := service.GetStatus(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, status.ClaimedToday)
+	assert.Equal(t, 4, status.Streak)
+	assert.Equal(t, 150, status.NextReward)
+	assert.Equal(t, time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC), status.NextClaimAt)
+
+	// A missed day starts the streak over
+	now = time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	status, err = service.GetStatus(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.False(t, status.ClaimedToday)
+	assert.Equal(t, 0, status.Streak)
+	assert.Equal(t, 100, status.NextReward)
+
+	reward, err = service.Claim(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reward.Claim.Streak)
+	assert.Equal(t, 100, reward.Claim.Coins)
+
+	var claims int64
+	db.Model(&models.DailyRewardClaim{}).Where("user_id = ?", user.ID).Count(&claims)
+	assert.Equal(t, int64(5), claims)
+}
+
+func TestClaimDailyRewardTwice(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+
+	rec := SendJSON(router, "POST", "/users/"+user.ID.String()+"/daily-reward/claim", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var first models.DailyReward
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &first))
+	assert.False(t, first.AlreadyClaimed)
+	assert.Equal(t, testConfig.Game.DailyReward(1), first.Claim.Coins)
+
+	rec = SendJSON(router, "POST", "/users/"+user.ID.String()+"/daily-reward/claim", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var second models.DailyReward
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &second))
+	assert.True(t, second.AlreadyClaimed)
+	assert.Equal(t, first.Balance, second.Balance)
+
+	rec = SendJSON(router, "GET", "/users/"+user.ID.String()+"/daily-reward", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var status models.DailyRewardStatus
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.True(t, status.ClaimedToday)
+	assert.Equal(t, 1, status.Streak)
+
+	rec = SendJSON(router, "POST", "/users/not-a-uuid/daily-reward/claim", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"good-api/internal/handlers"
+	"good-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// getProblem checks the content type and decodes the problem details of the response.
+func getProblem(t *testing.T, rec *httptest.ResponseRecorder) handlers.Problem {
+	t.Helper()
+	assert.Equal(t, handlers.ProblemContentType, rec.Header().Get("Content-Type"))
+
+	var problem handlers.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, rec.Code, problem.Status)
+	return problem
+}
+
+func TestUnknownUserIsNotFoundProblem(t *testing.T) {
+	router := SetupRouter()
+
+	req, _ := http.NewRequest("GET", "/users/"+uuid.NewString(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	problem := getProblem(t, rec)
+	assert.Equal(t, "user_not_found", problem.Code)
+	assert.Equal(t, "Not Found", problem.Title)
+	assert.NotEmpty(t, problem.RequestID)
+}
+
+func TestEnterTournamentWithoutCoins(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	SeedTestData(db)
+	user := CreateTestUser(db, "poor_user")
+	db.Model(&models.User{}).Where("id = ?", user.ID).Update("coins", 10)
+
+	req, _ := http.NewRequest("POST", "/tournaments/enter/"+user.ID.String(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code == http.StatusForbidden {
+		// Entry closes in the evening, before the coins are checked
+		assert.Equal(t, "tournament_entry_closed", getProblem(t, rec).Code)
+		return
+	}
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+	assert.Equal(t, "insufficient_coins", getProblem(t, rec).Code)
+}
+
+func TestMalformedIDIsInvalidRequestProblem(t *testing.T) {
+	router := SetupRouter()
+
+	req, _ := http.NewRequest("POST", "/tournaments/enter/not-a-uuid", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, handlers.CodeInvalidRequest, getProblem(t, rec).Code)
+}
+
+func TestMalformedLeaderboardIDIsInvalidRequestProblem(t *testing.T) {
+	router := SetupRouter()
+	id := uuid.NewString()
+
+	for _, url := range []string{
+		"/leaderboard/tournament?tournament_id=x",
+		"/leaderboard/tournament/rank?user_id=x&tournament_id=" + id,
+		"/leaderboard/tournament/rank?user_id=" + id + "&tournament_id=x",
+		"/leaderboard/team?tournament_id=x",
+		"/leaderboard/friends?user_id=x",
+		"/leaderboard/global?user_id=x",
+		"/leaderboard/country?country=TR&user_id=x",
+	} {
+		rec := SendJSON(router, "GET", url, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, url)
+		assert.Equal(t, handlers.CodeInvalidRequest, getProblem(t, rec).Code, url)
+	}
+}
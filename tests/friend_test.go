@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"encoding/json"
+	"good-api/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFriendRequestAndAccept(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	friend := CreateTestUser(db, "friend_user")
+
+	rec := SendJSON(router, "POST", "/friends/request", gin.H{"user_id": user.ID, "friend_id": friend.ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = SendJSON(router, "POST", "/friends/accept", gin.H{"user_id": friend.ID, "friend_id": user.ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ := http.NewRequest("GET", "/friends/"+user.ID.String(), nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var friends []models.User
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &friends))
+	assert.Len(t, friends, 1)
+}
+
+func TestBlockedUserCannotSendRequest(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	other := CreateTestUser(db, "blocked_user")
+
+	rec := SendJSON(router, "POST", "/friends/block", gin.H{"user_id": user.ID, "friend_id": other.ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = SendJSON(router, "POST", "/friends/request", gin.H{"user_id": other.ID, "friend_id": user.ID})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestFriendsLeaderboard(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	friend := CreateTestUser(db, "friend_user")
+	CreateTestUser(db, "stranger")
+
+	SendJSON(router, "POST", "/friends/request", gin.H{"user_id": user.ID, "friend_id": friend.ID})
+	SendJSON(router, "POST", "/friends/accept", gin.H{"user_id": friend.ID, "friend_id": user.ID})
+
+	req, _ := http.NewRequest("GET", "/leaderboard/friends?user_id="+user.ID.String(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var rows []models.LeaderboardRow
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rows))
+	if assert.Len(t, rows, 2) {
+		// The seeded user is level 15, the friend level 12
+		assert.Equal(t, user.ID, rows[0].UserID)
+		assert.Equal(t, 1, rows[0].Rank)
+		assert.Equal(t, friend.ID, rows[1].UserID)
+	}
+}
@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/grpcapi"
+	"good-api/internal/grpcapi/goodapiv1"
+	"good-api/internal/handlers"
+	"good-api/internal/logging"
+	"good-api/internal/ratelimit"
+	"good-api/internal/repositories"
+	"good-api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGRPC serves svc over an in-memory listener and returns a client connection to it.
+func dialGRPC(t *testing.T, svc grpcapi.Services) *grpc.ClientConn {
+	cfg := *testConfig
+	cfg.GRPC.LeaderboardPollInterval = 10 * time.Millisecond
+	return dialGRPCConfig(t, svc, cfg)
+}
+
+// dialGRPCConfig serves svc with cfg over an in-memory listener and returns a client connection to it.
+func dialGRPCConfig(t *testing.T, svc grpcapi.Services, cfg config.Config) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := grpcapi.New(svc, &cfg, logging.Discard())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to dial gRPC server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// testGRPCServices builds the gRPC services over the test database.
+func testGRPCServices() grpcapi.Services {
+	db := SetupTestDB()
+	SetupTestRedis()
+	logger := logging.Discard()
+
+	userRepo := repositories.NewUserRepository(db, logger)
+	tournamentRepo := repositories.NewTournamentRepository(db, logger)
+	teamRepo := repositories.NewTeamRepository(db, logger)
+	return grpcapi.Services{
+		UserService:        services.NewUserService(userRepo, teamRepo, testConfig.Game, logger),
+		UserRepo:           userRepo,
+		TournamentService:  services.NewTournamentService(tournamentRepo, userRepo, teamRepo, testConfig.Game, logger),
+		TournamentRepo:     tournamentRepo,
+		LeaderboardService: services.NewLeaderboardService(repositories.NewLeaderboardRepository(db), userRepo, logger),
+		Limiter:            ratelimit.New(testConfig.RateLimit, logger),
+	}
+}
+
+// errorReason returns the reason of the ErrorInfo detail of a gRPC error.
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestGRPCRejectsMalformedRequests(t *testing.T) {
+	// The requests are rejected before any service is called
+	conn := dialGRPC(t, grpcapi.Services{})
+	users := goodapiv1.NewUserServiceClient(conn)
+
+	_, err := users.GetUser(context.Background(), &goodapiv1.GetUserRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, handlers.CodeInvalidRequest, errorReason(err))
+
+	_, err = users.CreateUser(context.Background(), &goodapiv1.CreateUserRequest{Username: "a!", Country: "Atlantis"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, handlers.CodeValidationFailed, errorReason(err))
+
+	var violations []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				violations = append(violations, violation.Field)
+			}
+		}
+	}
+	assert.ElementsMatch(t, []string{"username", "country"}, violations)
+
+	leaderboards := goodapiv1.NewLeaderboardServiceClient(conn)
+	stream, err := leaderboards.WatchLeaderboard(context.Background(), &goodapiv1.WatchLeaderboardRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCRateLimit(t *testing.T) {
+	cfg := *testConfig
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.IPFactor = 0
+	cfg.RateLimit.Users = config.RateLimit{Requests: 1, Per: time.Minute, Burst: 1}
+	// The malformed requests are rejected before any service is called
+	users := goodapiv1.NewUserServiceClient(dialGRPCConfig(t, grpcapi.Services{Limiter: ratelimit.NewMemoryLimiter()}, cfg))
+
+	_, err := users.GetUser(context.Background(), &goodapiv1.GetUserRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = users.GetUser(context.Background(), &goodapiv1.GetUserRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "rate_limited", errorReason(err))
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	if assert.NotNil(t, retryInfo) {
+		assert.InDelta(t, time.Minute.Seconds(), retryInfo.GetRetryDelay().AsDuration().Seconds(), 1)
+	}
+
+	// Other users have their own buckets
+	_, err = users.GetUser(context.Background(), &goodapiv1.GetUserRequest{Id: "also-not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCEchoesRequestID(t *testing.T) {
+	conn := dialGRPC(t, grpcapi.Services{})
+	users := goodapiv1.NewUserServiceClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-123")
+	var header metadata.MD
+	_, err := users.GetUser(ctx, &goodapiv1.GetUserRequest{Id: "bad"}, grpc.Header(&header))
+	assert.Error(t, err)
+	assert.Equal(t, []string{"req-123"}, header.Get("x-request-id"))
+}
+
+func TestGRPCGetUser(t *testing.T) {
+	db := SetupTestDB()
+	user, _ := SeedTestData(db)
+	users := goodapiv1.NewUserServiceClient(dialGRPC(t, testGRPCServices()))
+
+	resp, err := users.GetUser(context.Background(), &goodapiv1.GetUserRequest{Id: user.ID.String()})
+	assert.NoError(t, err)
+	assert.Equal(t, user.Username, resp.GetUsername())
+
+	// Domain errors keep their code as the reason
+	_, err = users.GetUser(context.Background(), &goodapiv1.GetUserRequest{Id: "00000000-0000-0000-0000-000000000000"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, services.ErrUserNotFound.Code, errorReason(err))
+}
+
+func TestGRPCWatchLeaderboardSendsChanges(t *testing.T) {
+	db := SetupTestDB()
+	SeedTestData(db)
+	router := SetupRouter()
+	rec := SendAdminJSON(router, "POST", "/admin/leaderboard/rebuild", nil)
+	assert.Equal(t, 200, rec.Code)
+
+	svc := testGRPCServices()
+	leaderboards := goodapiv1.NewLeaderboardServiceClient(dialGRPC(t, svc))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := leaderboards.WatchLeaderboard(ctx, &goodapiv1.WatchLeaderboardRequest{Scope: goodapiv1.WatchLeaderboardRequest_SCOPE_GLOBAL, Limit: 10})
+	assert.NoError(t, err)
+	first, err := stream.Recv()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// A new top player changes the page, which is sent without asking again
+	user := CreateTestUser(db, "grpc_watcher")
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, svc.UserService.IncreaseLevel(ctx, user.ID))
+	}
+	next, err := stream.Recv()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, first.GetLeaderboard(), next.GetLeaderboard())
+	assert.Equal(t, user.ID.String(), next.GetLeaderboard()[0].GetUserId())
+}
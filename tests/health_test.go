@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"good-api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthz(t *testing.T) {
+	router := SetupRouter()
+
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestReadyz(t *testing.T) {
+	router := SetupRouter()
+
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var report services.ReadinessReport
+	err := json.Unmarshal(rec.Body.Bytes(), &report)
+	assert.NoError(t, err)
+	assert.True(t, report.Ready)
+	for _, name := range []string{"postgres", "redis", "migrations"} {
+		assert.Equal(t, services.StatusUp, report.Checks[name].Status, name)
+	}
+}
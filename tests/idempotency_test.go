@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"good-api/internal/handlers"
+	"good-api/internal/middleware"
+	"good-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// sendWithKey sends a request with an Idempotency-Key header.
+func sendWithKey(router *gin.Engine, method, url, key string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRetriedScoreUpdateCountsOnce(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, tournament := SeedTestData(db)
+	key := uuid.NewString()
+
+	first := sendWithKey(router, "POST", "/tournaments/update-score/"+user.ID.String(), key, nil)
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	retry := sendWithKey(router, "POST", "/tournaments/update-score/"+user.ID.String(), key, nil)
+	assert.Equal(t, first.Code, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+
+	var participant models.TournamentParticipant
+	db.Where("tournament_id = ? AND user_id = ?", tournament.ID, user.ID).First(&participant)
+	assert.Equal(t, user.Level+1, participant.Level)
+
+	// A new key is a new update
+	rec := sendWithKey(router, "POST", "/tournaments/update-score/"+user.ID.String(), uuid.NewString(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(middleware.IdempotentReplayedHeader))
+}
+
+func TestIdempotencyKeyReuseWithDifferentPayload(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	leader, _ := SeedTestData(db)
+	other := CreateTestUser(db, "other_user")
+	teamID := uuid.NewString()
+	key := uuid.NewString()
+
+	first := sendWithKey(router, "POST", "/tournaments/team/enter/"+teamID, key, gin.H{"user_id": leader.ID})
+	assert.NotEqual(t, http.StatusUnprocessableEntity, first.Code)
+
+	rec := sendWithKey(router, "POST", "/tournaments/team/enter/"+teamID, key, gin.H{"user_id": other.ID})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "idempotency_key_reused")
+}
+
+func TestIdempotencyKeyScopedToBodyUser(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	other := CreateTestUser(db, "other_user")
+	setShopItem(t, db, models.BoosterHammer, 100)
+	key := uuid.NewString()
+
+	// Purchases of different users never share a key, even from the same client
+	rec := sendWithKey(router, "POST", "/shop/purchase", key, handlers.PurchaseRequest{UserID: user.ID.String(), ItemID: models.BoosterHammer})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = sendWithKey(router, "POST", "/shop/purchase", key, handlers.PurchaseRequest{UserID: other.ID.String(), ItemID: models.BoosterHammer})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(middleware.IdempotentReplayedHeader))
+
+	var stored models.User
+	db.First(&stored, "id = ?", other.ID)
+	assert.Equal(t, other.Coins-100, stored.Coins)
+}
@@ -1,10 +1,15 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"good-api/internal/cache"
+	"good-api/internal/models"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -64,3 +69,110 @@ func TestGetTournamentRank(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	fmt.Println("All good mate")
 }
+
+func TestGlobalLeaderboardAfterRebuild(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+
+	req, _ := http.NewRequest("POST", "/admin/leaderboard/rebuild", nil)
+	req.Header.Set("Authorization", "Bearer "+testConfig.Server.AdminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req, _ = http.NewRequest("GET", "/leaderboard/global?user_id="+user.ID.String(), nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var page models.LeaderboardPage
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Len(t, page.Leaderboard, 1)
+	if assert.NotNil(t, page.Me) {
+		assert.Equal(t, 1, page.Me.Rank)
+		assert.Equal(t, user.Level, page.Me.Level)
+	}
+}
+
+func TestGlobalLeaderboardChangesAreJournaled(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	user, _ := SeedTestData(db)
+	ctx := context.Background()
+	since := time.Now().Add(-time.Second)
+
+	assert.NoError(t, cache.AddUserToGlobalLeaderboards(ctx, user.ID, user.Country, user.Level+2))
+	// A level read before a level-up does not lower the rank
+	assert.NoError(t, cache.AddUserToGlobalLeaderboards(ctx, user.ID, user.Country, user.Level))
+	_, level, ok, err := cache.GetGlobalRank(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, user.Level+2, level)
+
+	// A rebuild started before the change applies it again from Postgres
+	changed, err := cache.GetGlobalLeaderboardChanges(ctx, since)
+	assert.NoError(t, err)
+	assert.Contains(t, changed, user.ID)
+
+	changed, err = cache.GetGlobalLeaderboardChanges(ctx, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.NotContains(t, changed, user.ID)
+}
+
+func TestGetGlobalAndCountryRank(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+
+	req, _ := http.NewRequest("POST", "/admin/leaderboard/rebuild", nil)
+	req.Header.Set("Authorization", "Bearer "+testConfig.Server.AdminToken)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, url := range []string{"/leaderboard/global/rank", "/leaderboard/country/rank"} {
+		req, _ = http.NewRequest("GET", url+"?user_id="+user.ID.String(), nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var info models.RankInfo
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+		assert.Equal(t, 1, info.Rank)
+		assert.Equal(t, user.Username, info.Me.Username)
+		assert.Empty(t, info.Above)
+	}
+
+	// Users who never competed are not ranked
+	other := CreateTestUser(db, "not_competing")
+	req, _ = http.NewRequest("GET", "/leaderboard/global/rank?user_id="+other.ID.String(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGetCountries(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	SeedTestData(db)
+
+	req, _ := http.NewRequest("POST", "/admin/leaderboard/rebuild", nil)
+	req.Header.Set("Authorization", "Bearer "+testConfig.Server.AdminToken)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest("GET", "/countries", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var result []struct {
+		Code        string `json:"code"`
+		Name        string `json:"name"`
+		PlayerCount int    `json:"player_count"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "TR", result[0].Code)
+		assert.Equal(t, "Turkey", result[0].Name)
+		assert.Equal(t, 1, result[0].PlayerCount)
+	}
+}
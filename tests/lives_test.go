@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"good-api/internal/models"
+	"good-api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegenerateLives(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	regen := 30 * time.Minute
+
+	tests := []struct {
+		name      string
+		lives     int
+		now       time.Time
+		wantLives int
+		wantSince time.Time
+	}{
+		{"nothing regenerated yet", 2, since.Add(29 * time.Minute), 2, since},
+		{"one life back", 2, since.Add(45 * time.Minute), 3, since.Add(30 * time.Minute)},
+		{"two lives back", 0, since.Add(time.Hour), 2, since.Add(time.Hour)},
+		{"capped at the max", 1, since.Add(10 * time.Hour), 5, since.Add(10 * time.Hour)},
+		{"already full", 5, since.Add(time.Minute), 5, since.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lives, from := models.RegenerateLives(tt.lives, since, tt.now, 5, regen)
+			assert.Equal(t, tt.wantLives, lives)
+			assert.Equal(t, tt.wantSince, from)
+		})
+	}
+}
+
+func TestNewLivesRegenerationTimes(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	user := &models.User{Lives: 1, LivesUpdatedAt: since, Coins: 300}
+
+	lives := models.NewLives(user, since.Add(40*time.Minute), 5, 30*time.Minute)
+	assert.Equal(t, 2, lives.Lives)
+	assert.Equal(t, 300, lives.Coins)
+	if assert.NotNil(t, lives.NextLifeAt) && assert.NotNil(t, lives.FullAt) {
+		assert.Equal(t, since.Add(time.Hour), *lives.NextLifeAt)
+		assert.Equal(t, since.Add(2*time.Hour), *lives.FullAt)
+	}
+
+	full := models.NewLives(user, since.Add(3*time.Hour), 5, 30*time.Minute)
+	assert.Equal(t, 5, full.Lives)
+	assert.Nil(t, full.NextLifeAt)
+	assert.Nil(t, full.FullAt)
+}
+
+func TestFailedLevelsCostLives(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	maxLives := testConfig.Game.MaxLives
+
+	rec := SendJSON(router, "GET", "/users/"+user.ID.String()+"/lives", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var lives models.Lives
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &lives))
+	assert.Equal(t, maxLives, lives.Lives)
+	assert.Nil(t, lives.NextLifeAt)
+
+	for i := 1; i <= maxLives; i++ {
+		rec = SendJSON(router, "POST", "/tournaments/update-score/"+user.ID.String()+"?result=failed", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &lives))
+		assert.Equal(t, maxLives-i, lives.Lives)
+	}
+	assert.NotNil(t, lives.NextLifeAt)
+
+	// Without lives, levels can be neither played nor failed
+	rec = SendJSON(router, "POST", "/tournaments/update-score/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, services.ErrOutOfLives.Code, getProblem(t, rec).Code)
+	rec = SendJSON(router, "POST", "/tournaments/update-score/"+user.ID.String()+"?result=failed", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var participant models.TournamentParticipant
+	db.Where("user_id = ?", user.ID).First(&participant)
+	assert.Equal(t, user.Level, participant.Level, "failed levels do not score")
+
+	// Lives come back over time
+	db.Model(&models.User{}).Where("id = ?", user.ID).
+		Update("lives_updated_at", time.Now().UTC().Add(-testConfig.Game.LifeRegenInterval))
+	rec = SendJSON(router, "POST", "/tournaments/update-score/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = SendJSON(router, "POST", "/tournaments/update-score/"+user.ID.String()+"?result=lost", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRefillLives(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	cost := testConfig.Game.LivesRefillCost
+
+	rec := SendJSON(router, "POST", "/users/"+user.ID.String()+"/lives/refill", nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, services.ErrLivesFull.Code, getProblem(t, rec).Code)
+
+	db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{"lives": 0, "coins": cost - 1})
+	rec = SendJSON(router, "POST", "/users/"+user.ID.String()+"/lives/refill", nil)
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+
+	db.Model(&models.User{}).Where("id = ?", user.ID).Update("coins", cost+100)
+	rec = SendJSON(router, "POST", "/users/"+user.ID.String()+"/lives/refill", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var lives models.Lives
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &lives))
+	assert.Equal(t, testConfig.Game.MaxLives, lives.Lives)
+	assert.Equal(t, 100, lives.Coins)
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"good-api/internal/logging"
+	"good-api/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDHeader(t *testing.T) {
+	router := SetupRouter()
+
+	// A request ID is generated when the client does not send one
+	req, _ := http.NewRequest("GET", "/users/", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.NotEmpty(t, rec.Header().Get(middleware.RequestIDHeader))
+
+	// A request ID sent by the client is echoed back
+	req, _ = http.NewRequest("GET", "/users/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "test-request-id")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, "test-request-id", rec.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestLoggerAddsRequestIDAndRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewWithWriter(&buf, "json", "info")
+
+	ctx := logging.WithRequestID(context.Background(), "abc-123")
+	logger.InfoContext(ctx, "connecting", "user", "postgres", "password", "hunter2", "api_key", "k")
+
+	var record map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &record)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc-123", record["request_id"])
+	assert.Equal(t, "postgres", record["user"])
+	assert.Equal(t, logging.Redacted, record["password"])
+	assert.Equal(t, logging.Redacted, record["api_key"])
+	assert.NotContains(t, buf.String(), "hunter2")
+}
@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+
+	req, _ := http.NewRequest("POST", "/tournaments/enter/"+user.ID.String(), nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+
+	// Requests are recorded by route template, not by the requested URL
+	assert.Contains(t, body, `goodapi_http_request_duration_seconds_count{method="POST",route="/tournaments/enter/:id"`)
+	assert.NotContains(t, body, user.ID.String())
+
+	// The entry is counted as accepted or rejected depending on the time of day
+	assert.Contains(t, body, "goodapi_tournament_entries")
+}
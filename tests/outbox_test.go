@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"good-api/internal/logging"
+	"good-api/internal/models"
+	"good-api/internal/outbox"
+	"good-api/internal/repositories"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSink remembers the events it received and fails while fail is set.
+type recordingSink struct {
+	received []outbox.Envelope
+	fail     bool
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Deliver(_ context.Context, envelope outbox.Envelope) error {
+	if s.fail {
+		return assert.AnError
+	}
+	s.received = append(s.received, envelope)
+	return nil
+}
+
+func TestScoreUpdateRecordsLevelEvent(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, tournament := SeedTestData(db)
+
+	rec := SendJSON(router, "POST", "/tournaments/update-score/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	events, err := repositories.NewOutboxRepository(db).GetEvents(context.Background(), user.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, models.EventLevelCompleted, events[0].Type)
+
+		var payload models.LevelCompletedEvent
+		assert.NoError(t, json.Unmarshal([]byte(events[0].Payload), &payload))
+		assert.Equal(t, user.Level+1, payload.Level)
+		assert.Equal(t, tournament.ID, *payload.TournamentID)
+	}
+}
+
+func TestFinishTournamentRecordsEventOnce(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	_, tournament := SeedTestData(db)
+
+	SendJSON(router, "POST", "/tournaments/finish/"+tournament.ID.String(), nil)
+	// Finishing again resumes the payout but the tournament only finished once
+	SendJSON(router, "POST", "/tournaments/finish/"+tournament.ID.String(), nil)
+
+	events, err := repositories.NewOutboxRepository(db).GetEvents(context.Background(), tournament.ID)
+	assert.NoError(t, err)
+	var finished int
+	for _, event := range events {
+		if event.Type == models.EventTournamentFinished {
+			finished++
+		}
+	}
+	assert.Equal(t, 1, finished)
+}
+
+func TestDispatcherRetriesFailedEvents(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	SendJSON(router, "POST", "/tournaments/update-score/"+user.ID.String(), nil)
+
+	repo := repositories.NewOutboxRepository(db)
+	sink := &recordingSink{fail: true}
+	cfg := testConfig.Outbox
+	dispatcher := outbox.NewDispatcher(repo, []outbox.Sink{sink}, cfg, logging.Discard())
+
+	claimed, err := dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+
+	events, _ := repo.GetEvents(context.Background(), user.ID)
+	assert.Nil(t, events[0].DispatchedAt)
+	assert.Equal(t, 1, events[0].Attempts)
+	assert.NotEmpty(t, events[0].LastError)
+
+	// The retry waits for its backoff
+	claimed, _ = dispatcher.DispatchPending(context.Background())
+	assert.Equal(t, 0, claimed)
+
+	db.Model(&models.OutboxEvent{}).Where("id = ?", events[0].ID).Update("available_at", time.Now().UTC())
+	sink.fail = false
+	claimed, err = dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+
+	events, _ = repo.GetEvents(context.Background(), user.ID)
+	assert.NotNil(t, events[0].DispatchedAt)
+	if assert.Len(t, sink.received, 1) {
+		assert.Equal(t, events[0].ID, sink.received[0].ID)
+		assert.Equal(t, models.EventLevelCompleted, sink.received[0].Type)
+	}
+}
+
+func TestWebhookSinkPostsEnvelope(t *testing.T) {
+	var got outbox.Envelope
+	var headers http.Header
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	envelope := outbox.Envelope{
+		ID:          uuid.New(),
+		Type:        models.EventRewardPaid,
+		AggregateID: uuid.New(),
+		OccurredAt:  time.Now().UTC().Truncate(time.Second),
+		Payload:     json.RawMessage(`{"coins":5000}`),
+	}
+	sink := outbox.NewWebhookSink(receiver.URL, time.Second)
+	assert.NoError(t, sink.Deliver(context.Background(), envelope))
+
+	assert.Equal(t, envelope.ID, got.ID)
+	assert.JSONEq(t, `{"coins":5000}`, string(got.Payload))
+	assert.Equal(t, envelope.ID.String(), headers.Get(outbox.EventIDHeader))
+	assert.Equal(t, models.EventRewardPaid, headers.Get(outbox.EventTypeHeader))
+}
+
+func TestWebhookSinkFailsOnErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	sink := outbox.NewWebhookSink(receiver.URL, time.Second)
+	assert.Error(t, sink.Deliver(context.Background(), outbox.Envelope{ID: uuid.New(), Payload: json.RawMessage(`{}`)}))
+}
+
+func TestWriterSinkWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	sink := outbox.NewWriterSink("buffer", &buf)
+
+	first := outbox.Envelope{ID: uuid.New(), Type: models.EventTournamentEntered, Payload: json.RawMessage(`{}`)}
+	second := outbox.Envelope{ID: uuid.New(), Type: models.EventTournamentFinished, Payload: json.RawMessage(`{}`)}
+	assert.NoError(t, sink.Deliver(context.Background(), first))
+	assert.NoError(t, sink.Deliver(context.Background(), second))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if assert.Len(t, lines, 2) {
+		var decoded outbox.Envelope
+		assert.NoError(t, json.Unmarshal(lines[1], &decoded))
+		assert.Equal(t, second.ID, decoded.ID)
+	}
+}
+
+func TestOutboxBackoffDoublesUpToLimit(t *testing.T) {
+	dispatcher := outbox.NewDispatcher(nil, nil, testConfig.Outbox, logging.Discard())
+	assert.Equal(t, time.Second, dispatcher.Backoff(1))
+	assert.Equal(t, 8*time.Second, dispatcher.Backoff(4))
+	assert.Equal(t, time.Hour, dispatcher.Backoff(40))
+
+	cfg := testConfig.Outbox
+	cfg.BaseBackoff = 10 * time.Second
+	cfg.MaxBackoff = time.Minute
+	dispatcher = outbox.NewDispatcher(nil, nil, cfg, logging.Discard())
+	assert.Equal(t, 40*time.Second, dispatcher.Backoff(3))
+	assert.Equal(t, time.Minute, dispatcher.Backoff(10))
+}
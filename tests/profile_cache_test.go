@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"good-api/internal/logging"
+	"good-api/internal/metrics"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileCacheServesHitsUntilInvalidated(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	user, _ := SeedTestData(db)
+	repo := repositories.NewCachedUserRepository(db, logging.Discard(), time.Minute)
+	ctx := context.Background()
+
+	hits := testutil.ToFloat64(metrics.ProfileCacheLookups.WithLabelValues("hit"))
+	misses := testutil.ToFloat64(metrics.ProfileCacheLookups.WithLabelValues("miss"))
+
+	_, err := repo.GetUserByID(ctx, user.ID)
+	assert.NoError(t, err)
+
+	// A change that skips the repository is not seen while the profile is cached
+	db.Model(&models.User{}).Where("id = ?", user.ID).Update("country", "DE")
+	cached, err := repo.GetUserByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.Country, cached.Country)
+	assert.Equal(t, misses+1, testutil.ToFloat64(metrics.ProfileCacheLookups.WithLabelValues("miss")))
+	assert.Equal(t, hits+1, testutil.ToFloat64(metrics.ProfileCacheLookups.WithLabelValues("hit")))
+
+	// Writes through the repository drop the cached profile
+	assert.NoError(t, repo.AddCoins(ctx, user.ID, 50))
+	fresh, err := repo.GetUserByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "DE", fresh.Country)
+	assert.Equal(t, user.Coins+50, fresh.Coins)
+
+	_, err = repo.LevelUp(ctx, user.ID, 100)
+	assert.NoError(t, err)
+	fresh, err = repo.GetUserByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.Level+1, fresh.Level)
+
+	assert.NoError(t, repo.DeleteUser(ctx, user.ID))
+	_, err = repo.GetUserByID(ctx, user.ID)
+	assert.Error(t, err)
+}
+
+func TestProfileCacheCollapsesConcurrentMisses(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	user, _ := SeedTestData(db)
+	repo := repositories.NewCachedUserRepository(db, logging.Discard(), time.Minute)
+
+	var wg sync.WaitGroup
+	users := make([]*models.User, 20)
+	for i := range users {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			users[i], _ = repo.GetUserByID(context.Background(), user.ID)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, got := range users {
+		if assert.NotNil(t, got) {
+			assert.Equal(t, user.ID, got.ID)
+		}
+	}
+	// Callers sharing a load get their own copies
+	users[0].Coins = -1
+	assert.Equal(t, user.Coins, users[1].Coins)
+}
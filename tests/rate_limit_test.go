@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/handlers"
+	"good-api/internal/middleware"
+	"good-api/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// rateLimitedRouter serves GET /users/:id limited to 2 requests a minute per user,
+// and 4 a minute per client IP.
+func rateLimitedRouter(limiter ratelimit.Limiter) *gin.Engine {
+	limit := config.RateLimit{Requests: 2, Per: time.Minute, Burst: 2}
+	router := gin.New()
+	router.GET("/users/:id", middleware.RateLimit(limiter, "users", limit, middleware.ByUserParam("id"), limit.Scaled(2)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func sendGet(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitRejectsOverBurst(t *testing.T) {
+	router := rateLimitedRouter(ratelimit.NewMemoryLimiter())
+
+	first := sendGet(router, "/users/1")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("X-RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusOK, sendGet(router, "/users/1").Code)
+
+	rec := sendGet(router, "/users/1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, handlers.ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "60", rec.Header().Get("X-RateLimit-Reset"))
+}
+
+func TestRateLimitKeepsIdentitiesApart(t *testing.T) {
+	router := rateLimitedRouter(ratelimit.NewMemoryLimiter())
+
+	sendGet(router, "/users/1")
+	sendGet(router, "/users/1")
+	assert.Equal(t, http.StatusTooManyRequests, sendGet(router, "/users/1").Code)
+
+	// Another user has a bucket of their own
+	assert.Equal(t, http.StatusOK, sendGet(router, "/users/2").Code)
+}
+
+func TestRateLimitCapsClientsRotatingUsers(t *testing.T) {
+	router := rateLimitedRouter(ratelimit.NewMemoryLimiter())
+
+	// Every request names a new user, but they all come from the same client IP
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, http.StatusOK, sendGet(router, "/users/"+strconv.Itoa(i)).Code)
+	}
+	rec := sendGet(router, "/users/99")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "4", rec.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestRateLimitByUserBody(t *testing.T) {
+	limit := config.RateLimit{Requests: 1, Per: time.Minute, Burst: 1}
+	router := gin.New()
+	router.POST("/shop/purchase", middleware.RateLimit(ratelimit.NewMemoryLimiter(), "shop", limit, middleware.ByUserBody("user_id"), config.RateLimit{}), func(c *gin.Context) {
+		var body struct {
+			UserID string `json:"user_id"`
+		}
+		assert.NoError(t, c.ShouldBindJSON(&body), "the handler still reads the body")
+		c.String(http.StatusOK, body.UserID)
+	})
+	send := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/shop/purchase", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(`{"user_id":"a","item_id":"hammer"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a", rec.Body.String())
+	assert.Equal(t, http.StatusTooManyRequests, send(`{"user_id":"a"}`).Code)
+	assert.Equal(t, http.StatusOK, send(`{"user_id":"b"}`).Code)
+
+	// Without a user in the body, the client IP is limited
+	assert.Equal(t, http.StatusOK, send(`{}`).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(`not json`).Code)
+}
+
+func TestRateLimitDisabledGroups(t *testing.T) {
+	cfg := config.Default().RateLimit
+	cfg.Enabled = false
+	limits := middleware.NewLimits(ratelimit.NewMemoryLimiter(), cfg)
+
+	router := gin.New()
+	router.GET("/users/:id", limits("users", middleware.ByUserParam("id")), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	for i := 0; i < 30; i++ {
+		rec := sendGet(router, "/users/1")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+	}
+}
@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"good-api/internal/handlers"
+	"good-api/internal/models"
+	"good-api/internal/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setShopItem sets a catalog item's price and puts it on sale.
+func setShopItem(t *testing.T, db *gorm.DB, itemID string, price int) {
+	t.Helper()
+	assert.NoError(t, db.Model(&models.ShopItem{}).Where("id = ?", itemID).Updates(map[string]interface{}{"price": price, "available": true}).Error)
+}
+
+func TestShopCatalogHasBoosters(t *testing.T) {
+	router := SetupRouter()
+
+	rec := SendJSON(router, "GET", "/shop/items", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var items []models.ShopItem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	assert.Subset(t, ids, []string{models.BoosterHammer, models.BoosterShuffle, models.BoosterExtraMoves})
+}
+
+func TestShopPurchaseAndConsume(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	setShopItem(t, db, models.BoosterHammer, 300)
+
+	rec := SendJSON(router, "POST", "/shop/purchase", handlers.PurchaseRequest{UserID: user.ID.String(), ItemID: models.BoosterHammer, Quantity: 2})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var purchase models.Purchase
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &purchase))
+	assert.Equal(t, 600, purchase.Paid)
+	assert.Equal(t, 2, purchase.Quantity)
+	assert.Equal(t, user.Coins-600, purchase.Coins)
+
+	// 400 coins left, not enough for two more
+	rec = SendJSON(router, "POST", "/shop/purchase", handlers.PurchaseRequest{UserID: user.ID.String(), ItemID: models.BoosterHammer, Quantity: 2})
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+	assert.Equal(t, services.ErrPurchaseCoins.Code, getProblem(t, rec).Code)
+
+	rec = SendJSON(router, "POST", "/users/"+user.ID.String()+"/inventory/consume", handlers.ConsumeItemRequest{ItemID: models.BoosterHammer})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var item models.InventoryItem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &item))
+	assert.Equal(t, 1, item.Quantity)
+
+	rec = SendJSON(router, "POST", "/users/"+user.ID.String()+"/inventory/consume", handlers.ConsumeItemRequest{ItemID: models.BoosterHammer, Quantity: 2})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, services.ErrNotEnoughItems.Code, getProblem(t, rec).Code)
+
+	rec = SendJSON(router, "GET", "/users/"+user.ID.String()+"/inventory", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var inventory []models.InventoryItem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &inventory))
+	if assert.Len(t, inventory, 1) {
+		assert.Equal(t, models.BoosterHammer, inventory[0].ItemID)
+		assert.Equal(t, 1, inventory[0].Quantity)
+	}
+
+	var stored models.User
+	db.First(&stored, "id = ?", user.ID)
+	assert.Equal(t, user.Coins-600, stored.Coins)
+}
+
+func TestShopRejections(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	setShopItem(t, db, models.BoosterShuffle, 200)
+
+	rec := SendJSON(router, "POST", "/shop/purchase", handlers.PurchaseRequest{UserID: user.ID.String(), ItemID: "rainbow"})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, services.ErrShopItemNotFound.Code, getProblem(t, rec).Code)
+
+	rec = SendJSON(router, "POST", "/shop/purchase", handlers.PurchaseRequest{UserID: uuid.NewString(), ItemID: models.BoosterShuffle})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, services.ErrUserNotFound.Code, getProblem(t, rec).Code)
+
+	rec = SendJSON(router, "POST", "/shop/purchase", handlers.PurchaseRequest{UserID: user.ID.String(), ItemID: models.BoosterShuffle, Quantity: 100})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = SendJSON(router, "POST", "/users/"+user.ID.String()+"/inventory/consume", handlers.ConsumeItemRequest{ItemID: models.BoosterShuffle})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Only admins change the catalog
+	free := 0
+	rec = SendJSON(router, "PUT", "/admin/shop/items/"+models.BoosterShuffle, handlers.UpdateShopItemRequest{Price: &free})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	var item models.ShopItem
+	db.First(&item, "id = ?", models.BoosterShuffle)
+	assert.NotZero(t, item.Price)
+
+	// Admins take items off sale and change prices
+	unavailable := false
+	rec = SendAdminJSON(router, "PUT", "/admin/shop/items/"+models.BoosterShuffle, handlers.UpdateShopItemRequest{Available: &unavailable})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = SendJSON(router, "POST", "/shop/purchase", handlers.PurchaseRequest{UserID: user.ID.String(), ItemID: models.BoosterShuffle})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, services.ErrShopItemUnavailable.Code, getProblem(t, rec).Code)
+
+	price := -1
+	rec = SendAdminJSON(router, "PUT", "/admin/shop/items/"+models.BoosterShuffle, handlers.UpdateShopItemRequest{Price: &price})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	price = 150
+	rec = SendAdminJSON(router, "PUT", "/admin/shop/items/rainbow", handlers.UpdateShopItemRequest{Price: &price})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var stored models.User
+	db.First(&stored, "id = ?", user.ID)
+	assert.Equal(t, user.Coins, stored.Coins)
+}
+
+func TestShopConcurrentPurchasesDoNotOverdraw(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	setShopItem(t, db, models.BoosterExtraMoves, 300)
+
+	// 1000 coins buy three boosters, whatever the order of the requests
+	const requests = 10
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- SendJSON(router, "POST", "/shop/purchase", handlers.PurchaseRequest{UserID: user.ID.String(), ItemID: models.BoosterExtraMoves}).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	bought := 0
+	for code := range codes {
+		if code == http.StatusOK {
+			bought++
+		}
+	}
+	assert.Equal(t, 3, bought)
+
+	var stored models.User
+	db.First(&stored, "id = ?", user.ID)
+	assert.Equal(t, 100, stored.Coins)
+	var item models.InventoryItem
+	db.First(&item, "user_id = ? AND item_id = ?", user.ID, models.BoosterExtraMoves)
+	assert.Equal(t, 3, item.Quantity)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"good-api/internal/logging"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAndJoinTeam(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	leader, _ := SeedTestData(db)
+	member := CreateTestUser(db, "team_member")
+
+	rec := SendJSON(router, "POST", "/teams/", gin.H{"user_id": leader.ID, "name": "Blasters"})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var team models.Team
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &team))
+	assert.Equal(t, 1, team.MemberCount)
+
+	rec = SendJSON(router, "POST", "/teams/"+team.ID.String()+"/join", gin.H{"user_id": member.ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// A user can only be in one team
+	rec = SendJSON(router, "POST", "/teams/"+team.ID.String()+"/join", gin.H{"user_id": member.ID})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	req, _ := http.NewRequest("GET", "/teams/"+team.ID.String()+"/members", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var members []models.TeamMember
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &members))
+	assert.Len(t, members, 2)
+}
+
+func TestKickAndLeaveTeam(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	leader, _ := SeedTestData(db)
+	member := CreateTestUser(db, "team_member")
+
+	rec := SendJSON(router, "POST", "/teams/", gin.H{"user_id": leader.ID, "name": "Crushers"})
+	var team models.Team
+	json.Unmarshal(rec.Body.Bytes(), &team)
+	SendJSON(router, "POST", "/teams/"+team.ID.String()+"/join", gin.H{"user_id": member.ID})
+
+	// Members cannot kick the leader
+	rec = SendJSON(router, "POST", "/teams/"+team.ID.String()+"/kick", gin.H{"actor_id": member.ID, "user_id": leader.ID})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = SendJSON(router, "POST", "/teams/"+team.ID.String()+"/kick", gin.H{"actor_id": leader.ID, "user_id": member.ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The last member leaving deletes the team
+	rec = SendJSON(router, "POST", "/teams/"+team.ID.String()+"/leave", gin.H{"user_id": leader.ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var count int64
+	db.Model(&models.Team{}).Where("id = ?", team.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestEnterTeamTournament(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	leader, _ := SeedTestData(db)
+	member := CreateTestUser(db, "team_member")
+
+	rec := SendJSON(router, "POST", "/teams/", gin.H{"user_id": leader.ID, "name": "Matchers"})
+	var team models.Team
+	json.Unmarshal(rec.Body.Bytes(), &team)
+	SendJSON(router, "POST", "/teams/"+team.ID.String()+"/join", gin.H{"user_id": member.ID})
+
+	// Regular members cannot enter the team
+	rec = SendJSON(router, "POST", "/tournaments/team/enter/"+team.ID.String(), gin.H{"user_id": member.ID})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = SendJSON(router, "POST", "/tournaments/team/enter/"+team.ID.String(), gin.H{"user_id": leader.ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAddTeamEntryKeepsTheCap(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	leader, _ := SeedTestData(db)
+	repo := repositories.NewTeamRepository(db, logging.Discard())
+	ctx := context.Background()
+
+	tournament, err := repo.NewTeamTournament(ctx, 1)
+	assert.NoError(t, err)
+	first := models.Team{ID: uuid.New(), Name: "First", OwnerID: leader.ID}
+	second := models.Team{ID: uuid.New(), Name: "Second", OwnerID: leader.ID}
+	db.Create(&first)
+	db.Create(&second)
+
+	assert.NoError(t, repo.AddTeamEntry(ctx, tournament.ID, first.ID))
+	assert.ErrorIs(t, repo.AddTeamEntry(ctx, tournament.ID, second.ID), repositories.ErrTeamTournamentFull)
+
+	// A team cannot be entered twice, even when there is room
+	db.Model(&models.Tournament{}).Where("id = ?", tournament.ID).Update("max_users", 5)
+	assert.ErrorIs(t, repo.AddTeamEntry(ctx, tournament.ID, first.ID), repositories.ErrTeamEntryExists)
+
+	var stored models.Tournament
+	db.First(&stored, "id = ?", tournament.ID)
+	assert.Equal(t, 1, stored.UserCount)
+}
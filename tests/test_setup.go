@@ -1,21 +1,46 @@
 package tests
 
 import (
+	"bytes"
+	"encoding/json"
 	"good-api/internal/cache"
+	"good-api/internal/config"
+	"good-api/internal/database"
 	"good-api/internal/handlers"
+	"good-api/internal/logging"
+	"good-api/internal/middleware"
 	"good-api/internal/models"
+	"good-api/internal/ratelimit"
 	"good-api/internal/repositories"
 	"good-api/internal/services"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// testConfig is the default configuration pointed at the test database.
+var testConfig = newTestConfig()
+
+func newTestConfig() *config.Config {
+	cfg := config.Default()
+	cfg.Database.Name = "testdb"
+	// The suites send many requests from one IP; rate limiting has its own tests
+	cfg.RateLimit.Enabled = false
+	cfg.RateLimit.Backend = "memory"
+	cfg.Server.AdminToken = "test-admin-token"
+	// Webhook tests deliver to local receivers
+	cfg.Webhooks.AllowInsecureURLs = true
+	return &cfg
+}
+
 // Global test database instance
 var testDB *gorm.DB
 var once sync.Once
@@ -23,9 +48,7 @@ var once sync.Once
 // SetupTestDB initializes the test PostgreSQL database.
 func SetupTestDB() *gorm.DB {
 	once.Do(func() {
-		dsn := "host=match3-postgres user=postgres password=password dbname=testdb port=5432 sslmode=disable"
-
-		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		db, err := gorm.Open(postgres.Open(testConfig.Database.DSN()), &gorm.Config{})
 		if err != nil {
 			log.Fatalf("Failed to connect to test database: %v", err)
 		}
@@ -37,10 +60,18 @@ func SetupTestDB() *gorm.DB {
 		}
 
 		// Apply database migrations
-		err = db.AutoMigrate(&models.User{}, &models.Tournament{}, &models.TournamentParticipant{})
+		err = db.AutoMigrate(&models.User{}, &models.Tournament{}, &models.TournamentParticipant{},
+			&models.Team{}, &models.TeamMember{}, &models.TeamTournamentEntry{},
+			&models.Friendship{}, &models.UserContactHash{}, &models.SchemaMigration{},
+			&models.OutboxEvent{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.WebhookDeadLetter{},
+			&models.UsernameChange{}, &models.ShopItem{}, &models.InventoryItem{}, &models.DailyRewardClaim{},
+			&models.TournamentReward{})
 		if err != nil {
 			log.Fatalf("Failed to migrate test database: %v", err)
 		}
+		if err := database.RunMigrations(db, logging.Discard()); err != nil {
+			log.Fatalf("Failed to migrate test database: %v", err)
+		}
 
 		testDB = db
 	})
@@ -50,13 +81,26 @@ func SetupTestDB() *gorm.DB {
 
 // SetupTestRedis ensures Redis is running for tests.
 func SetupTestRedis() {
-	cache.InitRedis()
+	cache.InitRedis(testConfig.Redis, logging.Discard())
 }
 
 // SeedTestData inserts test users, tournaments, and participants before tests run.
 func SeedTestData(db *gorm.DB) (models.User, models.Tournament) {
 	// Clean up previous test data
 
+	db.Exec("DELETE FROM outbox_events")
+	db.Exec("DELETE FROM webhook_dead_letters")
+	db.Exec("DELETE FROM webhook_deliveries")
+	db.Exec("DELETE FROM webhook_subscriptions")
+	db.Exec("DELETE FROM username_changes")
+	db.Exec("DELETE FROM inventory_items")
+	db.Exec("DELETE FROM daily_reward_claims")
+	db.Exec("DELETE FROM tournament_rewards")
+	db.Exec("DELETE FROM friendships")
+	db.Exec("DELETE FROM user_contact_hashes")
+	db.Exec("DELETE FROM team_tournament_entries")
+	db.Exec("DELETE FROM team_members")
+	db.Exec("DELETE FROM teams")
 	db.Exec("DELETE FROM tournament_participants")
 	db.Exec("DELETE FROM tournaments")
 	db.Exec("DELETE FROM users")
@@ -82,7 +126,7 @@ func SeedTestData(db *gorm.DB) (models.User, models.Tournament) {
 		Username: "test_user",
 		Coins:    1000,
 		Level:    15,
-		Country:  "Turkey",
+		Country:  "TR",
 	}
 	db.Create(&user)
 
@@ -99,53 +143,162 @@ func SeedTestData(db *gorm.DB) (models.User, models.Tournament) {
 	return user, tournament
 }
 
+// CreateTestUser inserts an extra user next to the one created by SeedTestData.
+func CreateTestUser(db *gorm.DB, username string) models.User {
+	user := models.User{
+		ID:       uuid.New(),
+		Username: username,
+		Coins:    1000,
+		Level:    12,
+		Country:  "TR",
+	}
+	db.Create(&user)
+	return user
+}
+
+// SendJSON sends a request with a JSON body to the router and records the response.
+func SendJSON(router *gin.Engine, method, url string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// SendAdminJSON sends a request with a JSON body and the test admin token to the router.
+func SendAdminJSON(router *gin.Engine, method, url string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testConfig.Server.AdminToken)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
 func SetupRouter() *gin.Engine {
 	db := SetupTestDB()
 	SetupTestRedis()
+	logger := logging.Discard()
 
 	// repo
-	userRepo := repositories.NewUserRepository(db)
-	tournamentRepo := repositories.NewTournamentRepository(db)
+	userRepo := repositories.NewUserRepository(db, logger)
+	tournamentRepo := repositories.NewTournamentRepository(db, logger)
 	leaderboardRepo := repositories.NewLeaderboardRepository(db)
+	teamRepo := repositories.NewTeamRepository(db, logger)
+	friendRepo := repositories.NewFriendRepository(db)
 
 	// services
-	userService := services.NewUserService(userRepo)
-	tournamentService := services.NewTournamentService(tournamentRepo, userRepo)
-	leaderboardService := services.NewLeaderboardService(leaderboardRepo)
+	userService := services.NewUserService(userRepo, teamRepo, testConfig.Game, logger)
+	tournamentService := services.NewTournamentService(tournamentRepo, userRepo, teamRepo, testConfig.Game, logger)
+	leaderboardService := services.NewLeaderboardService(leaderboardRepo, userRepo, logger)
+	teamService := services.NewTeamService(teamRepo, userRepo)
+	friendService := services.NewFriendService(friendRepo, userRepo)
 
 	// Handlers
 	userHandler := handlers.NewUserHandlerwithService(userRepo, userService)
 	userJustHandler := handlers.NewUserHandlerwithRepo(userRepo)
 	tournamentHandler := handlers.NewTournamentHandler(tournamentService, tournamentRepo)
 	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService, leaderboardRepo)
+	teamHandler := handlers.NewTeamHandler(teamService)
+	friendHandler := handlers.NewFriendHandler(friendService)
+	countryHandler := handlers.NewCountryHandler(services.NewCountryService())
+	healthHandler := handlers.NewHealthHandler(services.NewHealthService(db))
+	adminHandler := handlers.NewAdminHandler(testConfig)
+	webhookHandler := handlers.NewWebhookHandler(services.NewWebhookService(repositories.NewWebhookRepository(db), testConfig.Webhooks))
+	shopHandler := handlers.NewShopHandler(services.NewShopService(repositories.NewShopRepository(db, logger), userRepo, logger))
+	dailyRewardHandler := handlers.NewDailyRewardHandler(services.NewDailyRewardService(repositories.NewDailyRewardRepository(db, logger), userRepo, testConfig.Game, logger))
 
 	// Routes
-	router := gin.Default()
-	userRoutes := router.Group("/users")
+	idempotent := middleware.Idempotency(testConfig.Server.IdempotencyTTL, logger)
+	limit := middleware.NewLimits(ratelimit.New(testConfig.RateLimit, logger), testConfig.RateLimit)
+	router := gin.New()
+	router.Use(gin.Recovery(), middleware.RequestLogger(logger), middleware.Metrics(), middleware.Timeout(testConfig.Server.RequestTimeout))
+	userRoutes := router.Group("/users", limit("users", middleware.ByUserParam("id")))
 	{
 		userRoutes.POST("/", userHandler.CreateUser)
 		userRoutes.GET("/:id", userJustHandler.GetUser)
+		userRoutes.POST("/batch", userJustHandler.GetUsersBatch)
 		userRoutes.GET("/", userJustHandler.GetAllUsers)
 		userRoutes.PUT("/:id", userHandler.UpdateUser)
+		userRoutes.PATCH("/:id/username", idempotent, userHandler.ChangeUsername)
 		userRoutes.DELETE("/:id", userHandler.DeleteUser)
+		userRoutes.GET("/:id/export", userHandler.ExportUser)
+		userRoutes.GET("/:id/lives", userHandler.GetLives)
+		userRoutes.POST("/:id/lives/refill", idempotent, userHandler.RefillLives)
+		userRoutes.GET("/:id/inventory", shopHandler.GetInventory)
+		userRoutes.POST("/:id/inventory/consume", idempotent, shopHandler.ConsumeItem)
+		userRoutes.GET("/:id/daily-reward", dailyRewardHandler.GetStatus)
+		userRoutes.POST("/:id/daily-reward/claim", idempotent, dailyRewardHandler.Claim)
 	}
 
-	tournamentRoutes := router.Group("/tournaments")
+	tournamentRoutes := router.Group("/tournaments", limit("tournaments", middleware.ByUserParam("id")))
 	{
-		tournamentRoutes.POST("/enter/:id", tournamentHandler.EnterTournament)
+		tournamentRoutes.POST("/enter/:id", idempotent, tournamentHandler.EnterTournament)
 		tournamentRoutes.GET("/:id", tournamentHandler.GetTournament)
 		tournamentRoutes.GET("/", tournamentHandler.GetAllTournaments)
-		tournamentRoutes.POST("/update-score/:id", tournamentHandler.UpdateScore)
+		tournamentRoutes.POST("/update-score/:id", limit("scores", middleware.ByUserParam("id")), idempotent, tournamentHandler.UpdateScore)
 		tournamentRoutes.POST("/finish/:id", tournamentHandler.FinishTournament)
 		tournamentRoutes.POST("/finish-all", tournamentHandler.FinishAllTournaments)
+		tournamentRoutes.POST("/team/enter/:id", idempotent, tournamentHandler.EnterTeamTournament)
+	}
+
+	teamRoutes := router.Group("/teams", limit("teams", middleware.ByClientIP))
+	{
+		teamRoutes.POST("/", teamHandler.CreateTeam)
+		teamRoutes.GET("/:id", teamHandler.GetTeam)
+		teamRoutes.GET("/:id/members", teamHandler.GetMembers)
+		teamRoutes.POST("/:id/join", teamHandler.JoinTeam)
+		teamRoutes.POST("/:id/leave", teamHandler.LeaveTeam)
+		teamRoutes.POST("/:id/kick", teamHandler.KickMember)
+		teamRoutes.PUT("/:id/role", teamHandler.SetMemberRole)
+	}
+
+	friendRoutes := router.Group("/friends", limit("friends", middleware.ByClientIP))
+	{
+		friendRoutes.POST("/request", friendHandler.SendRequest)
+		friendRoutes.POST("/accept", friendHandler.AcceptRequest)
+		friendRoutes.POST("/remove", friendHandler.RemoveFriend)
+		friendRoutes.POST("/block", friendHandler.BlockUser)
+		friendRoutes.PUT("/contact-hash", friendHandler.SetContactHash)
+		friendRoutes.POST("/import", friendHandler.ImportContacts)
+		friendRoutes.GET("/:id", friendHandler.GetFriends)
+		friendRoutes.GET("/:id/requests", friendHandler.GetIncomingRequests)
+	}
+
+	router.GET("/countries", countryHandler.GetCountries)
+	router.GET("/healthz", healthHandler.Healthz)
+	router.GET("/readyz", healthHandler.Readyz)
+	adminRoutes := router.Group("/admin", middleware.AdminAuth(testConfig.Server.AdminToken))
+	{
+		adminRoutes.GET("/config", adminHandler.GetConfig)
+		adminRoutes.POST("/leaderboard/rebuild", leaderboardHandler.RebuildLeaderboards)
+		adminRoutes.POST("/webhooks/subscriptions", webhookHandler.CreateSubscription)
+		adminRoutes.GET("/webhooks/subscriptions", webhookHandler.GetSubscriptions)
+		adminRoutes.DELETE("/webhooks/subscriptions/:id", webhookHandler.DeleteSubscription)
+		adminRoutes.GET("/webhooks/dead-letters", webhookHandler.GetDeadLetters)
+		adminRoutes.POST("/webhooks/dead-letters/:id/replay", webhookHandler.ReplayDeadLetter)
+		adminRoutes.PUT("/shop/items/:id", shopHandler.UpdateItem)
+	}
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	shopRoutes := router.Group("/shop", limit("shop", middleware.ByUserBody("user_id")))
+	{
+		shopRoutes.GET("/items", shopHandler.GetItems)
+		shopRoutes.POST("/purchase", idempotent, shopHandler.Purchase)
 	}
 
-	leaderboardRoutes := router.Group("/leaderboard")
+	leaderboardRoutes := router.Group("/leaderboard", limit("leaderboard", middleware.ByUserQuery("user_id")))
 	{
 		leaderboardRoutes.GET("/global", leaderboardHandler.GetGlobalLeaderboard)
 		leaderboardRoutes.GET("/country", leaderboardHandler.GetCountryLeaderboard)
+		leaderboardRoutes.GET("/global/rank", leaderboardHandler.GetGlobalRank)
+		leaderboardRoutes.GET("/country/rank", leaderboardHandler.GetCountryRank)
 		leaderboardRoutes.GET("/tournament", leaderboardHandler.GetTournamentLeaderboard)
 		leaderboardRoutes.GET("/tournament/rank", leaderboardHandler.GetTournamentRank)
+		leaderboardRoutes.GET("/team", leaderboardHandler.GetTeamLeaderboard)
+		leaderboardRoutes.GET("/friends", leaderboardHandler.GetFriendsLeaderboard)
 	}
 	return router
 
@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"good-api/internal/handlers"
+	"good-api/internal/logging"
+	"good-api/internal/middleware"
+	"good-api/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTimeoutReturns504(t *testing.T) {
+	db := SetupTestDB()
+	userHandler := handlers.NewUserHandlerwithRepo(repositories.NewUserRepository(db, logging.Discard()))
+
+	// The deadline passes before the query runs
+	router := gin.New()
+	router.Use(middleware.Timeout(time.Nanosecond))
+	router.GET("/users/", userHandler.GetAllUsers)
+
+	req, _ := http.NewRequest("GET", "/users/", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Contains(t, rec.Body.String(), "request timed out")
+}
@@ -1,33 +1,51 @@
 package tests
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"good-api/internal/cache"
+	"good-api/internal/logging"
+	"good-api/internal/models"
+	"good-api/internal/repositories"
+
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestEnterTournament(t *testing.T) {
 	db := SetupTestDB()
 	router := SetupRouter()
-	user, _ := SeedTestData(db)
+	SeedTestData(db)
+	// The seeded user is already in the seeded tournament
+	user := CreateTestUser(db, "new_player")
 
 	// First request for entering the tournament will be 200 given the conditions are met.
 	req1, _ := http.NewRequest("POST", "/tournaments/enter/"+user.ID.String(), nil)
 	rec1 := httptest.NewRecorder()
 	router.ServeHTTP(rec1, req1)
 
+	if rec1.Code == http.StatusForbidden {
+		// Entry closes in the evening
+		assert.Equal(t, "tournament_entry_closed", getProblem(t, rec1).Code)
+		return
+	}
 	assert.Equal(t, http.StatusOK, rec1.Code, "First is good to go")
 
-	/*
-		// If user has entered a tournament before, no further entry is allowed until the existing tournament is concluded.
-		req2, _ := http.NewRequest("POST", "/tournaments/enter/"+user.ID.String(), nil)
-		rec2 := httptest.NewRecorder()
-		router.ServeHTTP(rec2, req2)
+	// If user has entered a tournament before, no further entry is allowed until the existing tournament is concluded.
+	req2, _ := http.NewRequest("POST", "/tournaments/enter/"+user.ID.String(), nil)
+	rec2 := httptest.NewRecorder()
+	router.ServeHTTP(rec2, req2)
+
+	assert.Equal(t, http.StatusConflict, rec2.Code, "Second is no no")
+	assert.Equal(t, "tournament_already_entered", getProblem(t, rec2).Code)
 
-		assert.Equal(t, http.StatusBadRequest, rec2.Code, "Second is no no")
-	*/
+	var coins int
+	db.Model(&models.User{}).Where("id = ?", user.ID).Select("coins").Scan(&coins)
+	assert.Equal(t, user.Coins-testConfig.Game.EntryFee, coins, "the fee is only paid once")
 }
 
 func TestGetTournament(t *testing.T) {
@@ -98,3 +116,68 @@ func TestFinishAllTournaments(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 
 }
+
+func TestPayRewardOnlyOnce(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	user, tournament := SeedTestData(db)
+	repo := repositories.NewTournamentRepository(db, logging.Discard())
+	reward := models.RewardPaidEvent{TournamentID: tournament.ID, UserID: user.ID, Rank: 1, Coins: 5000}
+
+	assert.NoError(t, repo.PayReward(context.Background(), reward, true))
+	// A resumed payout must not pay or level up the user again
+	err := repo.PayReward(context.Background(), reward, true)
+	assert.ErrorIs(t, err, repositories.ErrRewardAlreadyPaid)
+
+	var stored models.User
+	db.First(&stored, "id = ?", user.ID)
+	assert.Equal(t, user.Coins+5000, stored.Coins)
+	var participant models.TournamentParticipant
+	db.First(&participant, "user_id = ?", user.ID)
+	assert.Equal(t, user.Level+1, participant.Level)
+
+	var rewards int64
+	db.Model(&models.TournamentReward{}).Where("tournament_id = ?", tournament.ID).Count(&rewards)
+	assert.Equal(t, int64(1), rewards)
+
+	var levelUps []models.OutboxEvent
+	db.Where("type = ?", models.EventLevelIncreased).Find(&levelUps)
+	if assert.Len(t, levelUps, 1) {
+		var payload models.LevelIncreasedEvent
+		assert.NoError(t, json.Unmarshal([]byte(levelUps[0].Payload), &payload))
+		assert.Equal(t, user.ID, payload.UserID)
+		assert.Equal(t, user.Level+1, payload.Level)
+	}
+}
+
+func TestFinishTournamentSkipsDeletedUser(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, tournament := SeedTestData(db)
+	other := CreateTestUser(db, "other_user")
+	db.Create(&models.TournamentParticipant{ID: uuid.New(), TournamentID: tournament.ID, UserID: other.ID, Level: other.Level})
+	ctx := context.Background()
+	cache.AddUserToLeaderboard(ctx, tournament.ID, user.ID, user.Level)
+	cache.AddUserToLeaderboard(ctx, tournament.ID, other.ID, other.Level)
+
+	rec := SendJSON(router, "DELETE", "/users/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	leaderboard, err := cache.GetTournamentLeaderboard(ctx, tournament.ID, testConfig.Game.TournamentSize)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{other.ID.String()}, leaderboard)
+
+	// A score update racing the deletion puts the user back on the leaderboard
+	cache.AddUserToLeaderboard(ctx, tournament.ID, user.ID, user.Level)
+	rec = SendJSON(router, "POST", "/tournaments/finish/"+tournament.ID.String(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var rewards int64
+	db.Model(&models.TournamentReward{}).Where("user_id = ?", user.ID).Count(&rewards)
+	assert.Equal(t, int64(0), rewards)
+	var stored models.User
+	db.First(&stored, "id = ?", other.ID)
+	assert.Equal(t, other.Coins+testConfig.Game.Reward(2), stored.Coins)
+	paid, err := cache.GetPayoutCheckpoint(ctx, tournament.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, paid)
+}
@@ -2,23 +2,31 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"good-api/internal/cache"
+	"good-api/internal/handlers"
+	"good-api/internal/logging"
 	"good-api/internal/models"
+	"good-api/internal/repositories"
+	"good-api/internal/services"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateUser(t *testing.T) {
 	router := SetupRouter()
 
-	newUser := models.User{
+	newUser := handlers.CreateUserRequest{
 		Username: "created_user",
-		Level:    10,
-		Coins:    1000,
 		Country:  "Turkey",
 	}
 
@@ -32,16 +40,38 @@ func TestCreateUser(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, rec.Code)
 
-	var created models.User
+	var created handlers.UserResponse
 	err := json.Unmarshal(rec.Body.Bytes(), &created)
 	assert.NoError(t, err)
 	assert.Equal(t, newUser.Username, created.Username)
+	assert.Equal(t, "TR", created.Country)
+	assert.Equal(t, 1, created.Level)
+	assert.Equal(t, 1000, created.Coins)
 	check := assert.NotEmpty(t, created.ID)
 	if check != true {
 		log.Fatalln("Problem")
 	}
 }
 
+func TestCreateUserUnknownCountry(t *testing.T) {
+	router := SetupRouter()
+
+	newUser := models.User{
+		Username: "nowhere_user",
+		Country:  "Atlantis",
+	}
+
+	payload, _ := json.Marshal(newUser)
+
+	req, _ := http.NewRequest("POST", "/users/", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	assert.NotEqual(t, http.StatusCreated, rec.Code)
+}
+
 func TestGetUser(t *testing.T) {
 	db := SetupTestDB()
 	router := SetupRouter()
@@ -89,10 +119,7 @@ func TestUpdateUser(t *testing.T) {
 	router := SetupRouter()
 	user, _ := SeedTestData(db)
 
-	update := models.User{
-		Level: 30,
-		Coins: 2000,
-	}
+	update := gin.H{"country": "Germany"}
 
 	payload, _ := json.Marshal(update)
 
@@ -107,13 +134,75 @@ func TestUpdateUser(t *testing.T) {
 
 	var updated models.User
 	db.First(&updated, "id = ?", user.ID)
-	assert.Equal(t, 30, updated.Level)
-	check := assert.Equal(t, 2000, updated.Coins)
+	check := assert.Equal(t, "DE", updated.Country)
 	if check != true {
 		log.Fatalln("Problem")
 	}
 }
 
+func TestUpdateUserKeepsCoinsAndLives(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+
+	// Cache the profile, then change coins and lives behind its back, like a purchase would
+	rec := SendJSON(router, "GET", "/users/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{"coins": 42, "lives": 1})
+
+	rec = SendJSON(router, "PUT", "/users/"+user.ID.String(), gin.H{"country": "DE"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var stored models.User
+	db.First(&stored, "id = ?", user.ID)
+	assert.Equal(t, "DE", stored.Country)
+	assert.Equal(t, 42, stored.Coins)
+	assert.Equal(t, 1, stored.Lives)
+	assert.Equal(t, user.Level, stored.Level)
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	router := SetupRouter()
+
+	rec := SendJSON(router, "POST", "/users/", gin.H{"username": "x!", "coins": 999999, "level": 99})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var problem handlers.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, handlers.CodeValidationFailed, problem.Code)
+
+	codes := make(map[string]string)
+	for _, field := range problem.Errors {
+		codes[field.Field] = field.Code
+	}
+	assert.Equal(t, "too_short", codes["username"])
+	assert.Equal(t, "read_only", codes["coins"])
+	assert.Equal(t, "read_only", codes["level"])
+
+	rec = SendJSON(router, "POST", "/users/", gin.H{"username": "sh1t_happens"})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"profanity"`)
+
+	rec = SendJSON(router, "POST", "/users/", gin.H{"username": "   "})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestUpdateUserRejectsReadOnlyFields(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+
+	rec := SendJSON(router, "PUT", "/users/"+user.ID.String(), gin.H{"country": "DE", "coins": 2000})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"coins"`)
+
+	var unchanged models.User
+	db.First(&unchanged, "id = ?", user.ID)
+	assert.Equal(t, user.Coins, unchanged.Coins)
+	assert.Equal(t, user.Country, unchanged.Country)
+}
+
 func TestDeleteUser(t *testing.T) {
 	db := SetupTestDB()
 	router := SetupRouter()
@@ -133,3 +222,275 @@ func TestDeleteUser(t *testing.T) {
 		log.Fatalln("Problem")
 	}
 }
+
+func TestGetUsersBatch(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	other := CreateTestUser(db, "batch_other")
+	missing := uuid.New()
+
+	rec := SendJSON(router, "POST", "/users/batch", handlers.BatchUsersRequest{
+		IDs: []string{other.ID.String(), missing.String(), user.ID.String(), other.ID.String()},
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp handlers.BatchUsersResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Users, 2) {
+		// Users come back in the order they were asked for, once each
+		assert.Equal(t, other.ID, resp.Users[0].ID)
+		assert.Equal(t, user.ID, resp.Users[1].ID)
+	}
+	assert.Equal(t, []uuid.UUID{missing}, resp.Missing)
+}
+
+func TestGetUsersBatchValidation(t *testing.T) {
+	router := SetupRouter()
+
+	rec := SendJSON(router, "POST", "/users/batch", gin.H{"ids": []string{}})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"too_few"`)
+
+	rec = SendJSON(router, "POST", "/users/batch", gin.H{"ids": []string{uuid.NewString(), "not-a-uuid"}})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"ids[1]"`)
+
+	ids := make([]string, 101)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	rec = SendJSON(router, "POST", "/users/batch", gin.H{"ids": ids})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"too_many"`)
+}
+
+func TestDeleteUserIsSoftAndLeavesEverything(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, tournament := SeedTestData(db)
+	friend := CreateTestUser(db, "delete_friend")
+	db.Create(&models.Friendship{ID: uuid.New(), UserID: user.ID, FriendID: friend.ID, Status: models.FriendshipAccepted})
+	db.Create(&models.Friendship{ID: uuid.New(), UserID: friend.ID, FriendID: user.ID, Status: models.FriendshipAccepted})
+	cache.AddUserToLeaderboard(context.Background(), tournament.ID, user.ID, user.Level)
+	SendAdminJSON(router, "POST", "/admin/leaderboard/rebuild", nil)
+
+	rec := SendJSON(router, "DELETE", "/users/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The row stays, hidden, until it is erased
+	var deleted models.User
+	assert.NoError(t, db.Unscoped().First(&deleted, "id = ?", user.ID).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+	rec = SendJSON(router, "GET", "/users/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var friendships int64
+	db.Model(&models.Friendship{}).Where("user_id = ? OR friend_id = ?", user.ID, user.ID).Count(&friendships)
+	assert.Zero(t, friendships)
+
+	ranked, err := cache.GetTournamentLeaderboard(context.Background(), tournament.ID, 0)
+	assert.NoError(t, err)
+	assert.NotContains(t, ranked, user.ID.String())
+	rec = SendJSON(router, "GET", "/leaderboard/global/rank?user_id="+user.ID.String(), nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = SendJSON(router, "DELETE", "/users/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestExportUser(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, tournament := SeedTestData(db)
+	db.Create(&models.UserContactHash{UserID: user.ID, Hash: "contact-hash"})
+
+	rec := SendJSON(router, "GET", "/users/"+user.ID.String()+"/export", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+
+	var export models.UserExport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export))
+	assert.Equal(t, user.Username, export.User.Username)
+	if assert.Len(t, export.Tournaments, 1) {
+		assert.Equal(t, tournament.ID, export.Tournaments[0].ID)
+	}
+	assert.Len(t, export.Participations, 1)
+	if assert.NotNil(t, export.ContactHash) {
+		assert.Equal(t, "contact-hash", export.ContactHash.Hash)
+	}
+	assert.Nil(t, export.TeamMembership)
+
+	rec = SendJSON(router, "GET", "/users/"+uuid.NewString()+"/export", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestExportDeletedUser(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	router := SetupRouter()
+	user, tournament := SeedTestData(db)
+
+	rec := SendJSON(router, "DELETE", "/users/"+user.ID.String(), nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Until the retention period has passed the account is still stored, so it can be exported
+	rec = SendJSON(router, "GET", "/users/"+user.ID.String()+"/export", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var export models.UserExport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export))
+	assert.Equal(t, user.ID, export.User.ID)
+	// The tournament results were anonymized at deletion
+	assert.Empty(t, export.Participations)
+	assert.Empty(t, export.Tournaments)
+	var participants []models.TournamentParticipant
+	db.Where("tournament_id = ?", tournament.ID).Find(&participants)
+	if assert.Len(t, participants, 1) {
+		assert.NotEqual(t, user.ID, participants[0].UserID)
+	}
+}
+
+func TestEraseDeletedUsersAfterRetention(t *testing.T) {
+	db := SetupTestDB()
+	SetupTestRedis()
+	user, tournament := SeedTestData(db)
+	recent := CreateTestUser(db, "recently_deleted")
+	logger := logging.Discard()
+	userRepo := repositories.NewUserRepository(db, logger)
+	service := services.NewUserService(userRepo, repositories.NewTeamRepository(db, logger), testConfig.Game, logger)
+
+	assert.NoError(t, service.DeleteUser(context.Background(), user.ID))
+	assert.NoError(t, service.DeleteUser(context.Background(), recent.ID))
+	db.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).Update("deleted_at", time.Now().UTC().Add(-48*time.Hour))
+
+	erased, err := service.EraseDeletedUsers(context.Background(), 24*time.Hour, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, erased)
+
+	var remaining int64
+	db.Unscoped().Model(&models.User{}).Where("id IN ?", []uuid.UUID{user.ID, recent.ID}).Count(&remaining)
+	assert.Equal(t, int64(1), remaining)
+
+	// The tournament keeps its result, no longer linked to the user
+	var participants []models.TournamentParticipant
+	db.Where("tournament_id = ?", tournament.ID).Find(&participants)
+	if assert.Len(t, participants, 1) {
+		assert.NotEqual(t, user.ID, participants[0].UserID)
+		assert.Equal(t, user.Level, participants[0].Level)
+	}
+}
+
+func changeUsername(router *gin.Engine, userID uuid.UUID, username string) *httptest.ResponseRecorder {
+	return SendJSON(router, "PATCH", "/users/"+userID.String()+"/username", handlers.ChangeUsernameRequest{Username: username})
+}
+
+func TestChangeUsername(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+
+	rec := changeUsername(router, user.ID, "renamed_user")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var updated handlers.UserResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, "renamed_user", updated.Username)
+	assert.Equal(t, user.Coins-testConfig.Game.UsernameChangeCost, updated.Coins)
+
+	var history []models.UsernameChange
+	db.Where("user_id = ?", user.ID).Find(&history)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "test_user", history[0].OldUsername)
+		assert.Equal(t, "renamed_user", history[0].NewUsername)
+		assert.WithinDuration(t, history[0].ChangedAt.Add(testConfig.Game.UsernameHoldPeriod), history[0].HeldUntil, time.Second)
+	}
+
+	// A second change has to wait for the cooldown
+	rec = changeUsername(router, user.ID, "renamed_again")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, services.ErrUsernameCooldown.Code, getProblem(t, rec).Code)
+
+	rec = SendJSON(router, "GET", "/users/"+user.ID.String()+"/export", nil)
+	var export models.UserExport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &export))
+	assert.Len(t, export.UsernameHistory, 1)
+}
+
+func TestChangeUsernameRejections(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	other := CreateTestUser(db, "other_user")
+
+	// Usernames are unique ignoring case
+	rec := changeUsername(router, user.ID, "OTHER_USER")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, services.ErrUsernameTaken.Code, getProblem(t, rec).Code)
+
+	rec = changeUsername(router, user.ID, "test_user")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, services.ErrUsernameUnchanged.Code, getProblem(t, rec).Code)
+
+	rec = changeUsername(router, user.ID, "x")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	db.Model(&models.User{}).Where("id = ?", other.ID).Update("coins", testConfig.Game.UsernameChangeCost-1)
+	rec = changeUsername(router, other.ID, "rich_user")
+	assert.Equal(t, http.StatusPaymentRequired, rec.Code)
+	assert.Equal(t, services.ErrUsernameChangeCoins.Code, getProblem(t, rec).Code)
+
+	var changes int64
+	db.Model(&models.UsernameChange{}).Count(&changes)
+	assert.Zero(t, changes)
+}
+
+func TestChangeUsernameHoldsOldName(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	user, _ := SeedTestData(db)
+	other := CreateTestUser(db, "other_user")
+
+	rec := changeUsername(router, user.ID, "new_name")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Nobody else can take the old name during the hold, in any case
+	rec = changeUsername(router, other.ID, "Test_User")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, services.ErrUsernameHeld.Code, getProblem(t, rec).Code)
+	rec = SendJSON(router, "POST", "/users/", handlers.CreateUserRequest{Username: "test_user"})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Once the hold is over the name is free again
+	db.Model(&models.UsernameChange{}).Where("user_id = ?", user.ID).Update("held_until", time.Now().UTC().Add(-time.Minute))
+	rec = changeUsername(router, other.ID, "Test_User")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCreateUserSameNameConcurrently(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	SeedTestData(db)
+
+	const requests = 10
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- SendJSON(router, "POST", "/users/", handlers.CreateUserRequest{Username: "racing_user"}).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	created := 0
+	for code := range codes {
+		if code == http.StatusCreated {
+			created++
+		} else {
+			assert.Equal(t, http.StatusConflict, code)
+		}
+	}
+	assert.Equal(t, 1, created)
+}
@@ -0,0 +1,251 @@
+package tests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"good-api/internal/config"
+	"good-api/internal/handlers"
+	"good-api/internal/logging"
+	"good-api/internal/models"
+	"good-api/internal/outbox"
+	"good-api/internal/repositories"
+	"good-api/internal/services"
+	"good-api/internal/webhooks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// webhookReceiver is a local partner endpoint that records deliveries and answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, r)
+		receiver.bodies = append(receiver.bodies, body)
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// deliverWebhooks dispatches the outbox to the webhook sink and sends the due deliveries.
+func deliverWebhooks(t *testing.T, db *gorm.DB, deliverer *webhooks.Deliverer) {
+	repo := repositories.NewWebhookRepository(db)
+	dispatcher := outbox.NewDispatcher(repositories.NewOutboxRepository(db), []outbox.Sink{webhooks.NewSink(repo)}, testConfig.Outbox, logging.Discard())
+	_, err := dispatcher.DispatchPending(context.Background())
+	assert.NoError(t, err)
+	_, err = deliverer.DeliverDue(context.Background())
+	assert.NoError(t, err)
+}
+
+func createSubscription(t *testing.T, url, eventType, secret string) handlers.WebhookSubscriptionCreated {
+	router := SetupRouter()
+	rec := SendAdminJSON(router, "POST", "/admin/webhooks/subscriptions", handlers.CreateWebhookSubscriptionRequest{
+		URL:        url,
+		EventTypes: []string{eventType},
+		Secret:     secret,
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created handlers.WebhookSubscriptionCreated
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	return created
+}
+
+func TestFinishAllTournamentsSendsSignedWebhook(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	_, tournament := SeedTestData(db)
+	receiver := newWebhookReceiver(t)
+	createSubscription(t, receiver.URL, models.EventTournamentFinished, "partner-secret")
+
+	rec := SendJSON(router, "POST", "/tournaments/finish-all", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	deliverer := webhooks.NewDeliverer(repositories.NewWebhookRepository(db), testConfig.Webhooks, logging.Discard())
+	deliverWebhooks(t, db, deliverer)
+
+	if !assert.Len(t, receiver.requests, 1) {
+		return
+	}
+	req, body := receiver.requests[0], receiver.bodies[0]
+	assert.Equal(t, models.EventTournamentFinished, req.Header.Get(outbox.EventTypeHeader))
+
+	// The signature is an HMAC-SHA256 of the timestamp and the body
+	mac := hmac.New(sha256.New, []byte("partner-secret"))
+	mac.Write([]byte(req.Header.Get(webhooks.TimestampHeader) + "."))
+	mac.Write(body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), req.Header.Get(webhooks.SignatureHeader))
+
+	var envelope outbox.Envelope
+	assert.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, tournament.ID, envelope.AggregateID)
+
+	// Dispatching again sends nothing new
+	deliverWebhooks(t, db, deliverer)
+	assert.Len(t, receiver.requests, 1)
+}
+
+func TestFailedWebhookIsDeadLetteredAndReplayed(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	_, tournament := SeedTestData(db)
+	receiver := newWebhookReceiver(t)
+	receiver.setStatus(http.StatusInternalServerError)
+	createSubscription(t, receiver.URL, models.EventTournamentFinished, "")
+
+	cfg := testConfig.Webhooks
+	cfg.MaxAttempts = 2
+	cfg.BaseBackoff = time.Millisecond
+	deliverer := webhooks.NewDeliverer(repositories.NewWebhookRepository(db), cfg, logging.Discard())
+
+	SendJSON(router, "POST", "/tournaments/finish/"+tournament.ID.String(), nil)
+	deliverWebhooks(t, db, deliverer)
+	time.Sleep(10 * time.Millisecond)
+	deliverWebhooks(t, db, deliverer)
+	assert.Len(t, receiver.requests, 2)
+
+	rec := SendAdminJSON(router, "GET", "/admin/webhooks/dead-letters", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var deadLetters []models.WebhookDeadLetter
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &deadLetters))
+	if !assert.Len(t, deadLetters, 1) {
+		return
+	}
+	assert.Equal(t, 2, deadLetters[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, deadLetters[0].LastStatusCode)
+
+	// No more attempts until it is replayed
+	time.Sleep(10 * time.Millisecond)
+	deliverWebhooks(t, db, deliverer)
+	assert.Len(t, receiver.requests, 2)
+
+	receiver.setStatus(http.StatusOK)
+	rec = SendAdminJSON(router, "POST", "/admin/webhooks/dead-letters/"+deadLetters[0].ID.String()+"/replay", nil)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	deliverWebhooks(t, db, deliverer)
+	assert.Len(t, receiver.requests, 3)
+
+	rec = SendAdminJSON(router, "POST", "/admin/webhooks/dead-letters/"+deadLetters[0].ID.String()+"/replay", nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = SendAdminJSON(router, "GET", "/admin/webhooks/dead-letters", nil)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+}
+
+func TestCreateWebhookSubscriptionValidation(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	SeedTestData(db)
+
+	rec := SendAdminJSON(router, "POST", "/admin/webhooks/subscriptions", handlers.CreateWebhookSubscriptionRequest{
+		URL:        "ftp://partner.example",
+		EventTypes: []string{models.EventTournamentFinished},
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "webhook_invalid_url")
+
+	rec = SendAdminJSON(router, "POST", "/admin/webhooks/subscriptions", handlers.CreateWebhookSubscriptionRequest{
+		URL:        "https://partner.example/hook",
+		EventTypes: []string{"tournament.started"},
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "webhook_unknown_event_type")
+
+	// A generated secret is shown once and never listed
+	created := createSubscription(t, "https://partner.example/hook", models.EventRewardPaid, "")
+	assert.Len(t, created.Secret, 64)
+
+	rec = SendAdminJSON(router, "GET", "/admin/webhooks/subscriptions", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), created.Secret)
+}
+
+func TestWebhookRoutesNeedAdminToken(t *testing.T) {
+	db := SetupTestDB()
+	router := SetupRouter()
+	SeedTestData(db)
+
+	rec := SendJSON(router, "POST", "/admin/webhooks/subscriptions", handlers.CreateWebhookSubscriptionRequest{
+		URL:        "https://attacker.example/hook",
+		EventTypes: []string{models.EventRewardPaid},
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = SendJSON(router, "GET", "/admin/webhooks/dead-letters", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = SendJSON(router, "POST", "/admin/webhooks/dead-letters/"+uuid.NewString()+"/replay", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	var subscriptions int64
+	db.Model(&models.WebhookSubscription{}).Count(&subscriptions)
+	assert.Zero(t, subscriptions)
+}
+
+func TestWebhookSubscriptionURLMustBePublic(t *testing.T) {
+	service := services.NewWebhookService(repositories.NewWebhookRepository(nil), config.WebhooksConfig{})
+	events := []string{models.EventRewardPaid}
+
+	for _, url := range []string{
+		"http://partner.example/hook",
+		"https://",
+		"https://localhost/hook",
+		"https://127.0.0.1/hook",
+		"https://[::1]:8443/hook",
+		"https://10.0.0.5/hook",
+		"https://192.168.1.10/hook",
+		"https://172.16.0.1/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[fe80::1]/hook",
+		"https://0.0.0.0/hook",
+	} {
+		_, err := service.CreateSubscription(context.Background(), url, events, "")
+		assert.Error(t, err, url)
+	}
+
+	_, err := service.CreateSubscription(context.Background(), "https://10.0.0.5/hook", events, "")
+	assert.ErrorIs(t, err, services.ErrWebhookPrivateURL)
+	_, err = service.CreateSubscription(context.Background(), "http://partner.example/hook", events, "")
+	assert.ErrorIs(t, err, services.ErrWebhookInvalidURL)
+
+	assert.NoError(t, webhooks.CheckURL("https://partner.example/hook"))
+	assert.NoError(t, webhooks.CheckURL("https://93.184.216.34/hook"))
+}
+
+func TestWebhookBackoffDoublesUpToLimit(t *testing.T) {
+	cfg := testConfig.Webhooks
+	cfg.BaseBackoff = 10 * time.Second
+	cfg.MaxBackoff = time.Minute
+	deliverer := webhooks.NewDeliverer(nil, cfg, logging.Discard())
+
+	assert.Equal(t, 10*time.Second, deliverer.Backoff(1))
+	assert.Equal(t, 40*time.Second, deliverer.Backoff(3))
+	assert.Equal(t, time.Minute, deliverer.Backoff(10))
+}
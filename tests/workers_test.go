@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"good-api/internal/logging"
+	"good-api/internal/workers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerGroupFinishesCurrentWork(t *testing.T) {
+	group := workers.NewGroup(context.Background(), logging.Discard())
+
+	finished := make(chan bool, 1)
+	group.Go("test", func(stopping, work context.Context) {
+		<-stopping.Done()
+		// Work in progress still completes after the stop signal
+		select {
+		case <-work.Done():
+			finished <- false
+		case <-time.After(10 * time.Millisecond):
+			finished <- true
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, group.Shutdown(ctx))
+	assert.True(t, <-finished)
+}
+
+func TestWorkerGroupInterruptsWorkAfterDeadline(t *testing.T) {
+	group := workers.NewGroup(context.Background(), logging.Discard())
+
+	interrupted := make(chan bool, 1)
+	group.Go("test", func(stopping, work context.Context) {
+		// A long payout that only stops when its work context is cancelled
+		select {
+		case <-work.Done():
+			interrupted <- true
+		case <-time.After(5 * time.Second):
+			interrupted <- false
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, group.Shutdown(ctx), context.DeadlineExceeded)
+	assert.True(t, <-interrupted)
+}